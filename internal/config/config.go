@@ -13,6 +13,9 @@ type Config struct {
 	GoogleDriveCredentials GoogleDriveCredentials
 	Youtube                Youtube
 	Telegram               Telegram
+	Queue                  Queue
+	ShortLink              ShortLink
+	History                History
 }
 
 func MustLoad() Config {
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// History локальная история метрик роликов
+type History struct {
+	Path string `env:"HISTORY_PATH" env-default:"data/metrics.history"`
+	// Retention сколько хранить снимки; 0 — хранить всё
+	Retention time.Duration `env:"HISTORY_RETENTION" env-default:"2160h"`
+}
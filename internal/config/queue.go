@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+type Queue struct {
+	JournalPath       string `env:"QUEUE_JOURNAL_PATH" env-default:"data/queue.journal"`
+	ResumeInterrupted bool   `env:"QUEUE_RESUME_INTERRUPTED" env-default:"true"`
+	// DrainTimeout сколько при остановке ждём завершения выполняющихся задач
+	DrainTimeout  time.Duration `env:"QUEUE_DRAIN_TIMEOUT" env-default:"2m"`
+	SchedulesPath string        `env:"SCHEDULES_PATH" env-default:"data/schedules.json"`
+	// CheckpointsDir контрольные точки парсинга ссылок, по файлу на задачу
+	CheckpointsDir string `env:"QUEUE_CHECKPOINTS_DIR" env-default:"data/checkpoints"`
+	// повтор задач, упавших на временной ошибке
+	MaxRetries     int           `env:"QUEUE_MAX_RETRIES" env-default:"3"`
+	RetryBaseDelay time.Duration `env:"QUEUE_RETRY_BASE_DELAY" env-default:"1m"`
+	RetryMaxDelay  time.Duration `env:"QUEUE_RETRY_MAX_DELAY" env-default:"30m"`
+}
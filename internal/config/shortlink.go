@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// ShortLink раскрытие коротких ссылок (vt.tiktok.com, vk.cc, instagr.am)
+type ShortLink struct {
+	Timeout  time.Duration `env:"SHORTLINK_TIMEOUT" env-default:"10s"`
+	MaxHops  int           `env:"SHORTLINK_MAX_HOPS" env-default:"5"`
+	CacheTTL time.Duration `env:"SHORTLINK_CACHE_TTL" env-default:"24h"`
+}
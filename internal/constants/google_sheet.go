@@ -1,7 +1,14 @@
 package constants
 
 const (
-	DataTable     = "🔴 Сырые данные"
-	AccountTable  = "🟡 Сырые данные по аккаунтам"
-	ProgressTable = "🔴 Прогресс парсинга"
+	DataTable           = "🔴 Сырые данные"
+	AccountTable        = "🟡 Сырые данные по аккаунтам"
+	AccountSummaryTable = "🟢 Сводка по аккаунтам"
+	ProgressTable       = "🔴 Прогресс парсинга"
+)
+
+// Колонки со ссылкой на ролик (0-based), по ним строки листов обновляются на месте
+const (
+	DataTableKeyColumn    = 0 // ссылка на ролик — первая колонка
+	AccountTableKeyColumn = 1 // первая колонка — ссылка на аккаунт, вторая — на ролик
 )
@@ -3,4 +3,5 @@ package constants
 const (
 	ParsingDateFormat        = "02.01.2006 15:04"
 	YoutubeParsingDateFormat = "2006-01-02T15:04:05Z"
+	RutubeDateFormat         = "2006-01-02T15:04:05" // московское время без зоны
 )
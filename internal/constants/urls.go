@@ -9,12 +9,21 @@ const (
 	DownloadVideos          = "/download_videos"
 	DownloadVideosGet       = "/download_videos_get"
 	MessageSend             = "/send"
+	Jobs                    = "/jobs"
+	JobByID                 = "/jobs/{id}"
+	DeadLetterJobs          = "/jobs/dead_letter"
+	RequeueJob              = "/jobs/{id}/requeue"
+	Schedules               = "/schedules"
+	ScheduleByID            = "/schedules/{id}"
+	MetricsHistory          = "/metrics_history"
 )
 
 // rapid api urls
 const (
 	RapidRealTimeInstagramScraperMediaInfo = "https://real-time-instagram-scraper-api1.p.rapidapi.com/v1/media_info"
 	RapidRealTimeInstagramScraperUserReels = "https://real-time-instagram-scraper-api1.p.rapidapi.com/v1/user_reels"
+	RapidRealTimeInstagramScraperUserPosts = "https://real-time-instagram-scraper-api1.p.rapidapi.com/v1/user_posts"
+	RapidRealTimeInstagramScraperUserInfo  = "https://real-time-instagram-scraper-api1.p.rapidapi.com/v1/user_info"
 	RapidTiktokScraper                     = "https://tiktok-scraper7.p.rapidapi.com/"
 	RapidTiktokUserSearch                  = "https://tiktok-scraper7.p.rapidapi.com/user/search"
 	RapidTiktokUserPorts                   = "https://tiktok-scraper7.p.rapidapi.com/user/posts"
@@ -26,4 +35,16 @@ const (
 	YoutubeVideos        = "https://www.googleapis.com/youtube/v3/videos"
 	YoutubeChannels      = "https://www.googleapis.com/youtube/v3/channels"
 	YoutubePlaylistItems = "https://www.googleapis.com/youtube/v3/playlistItems"
+	YoutubeShortsPage    = "https://www.youtube.com/shorts/"
+)
+
+// telegram web preview
+const (
+	TelegramWeb = "https://t.me"
+)
+
+// rutube public api
+const (
+	RutubeVideo         = "https://rutube.ru/api/video/"
+	RutubeChannelVideos = "https://rutube.ru/api/video/person/"
 )
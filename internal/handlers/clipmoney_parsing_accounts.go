@@ -13,6 +13,9 @@ type (
 	// ClipMoneyParsingAccountRequest represents the request body for parsing account
 	ClipMoneyParsingAccountRequest struct {
 		AccountUrl string `json:"account_url" example:"https://vk.ru/id41699827"` // Account URL
+		FullFeed   bool   `json:"full_feed" example:"false"`                      // Instagram only: walk the whole feed (photos, carousels, reels), not only reels
+		Since      string `json:"since" example:"2024-05-01"`                     // Only videos published on or after this day (YYYY-MM-DD or DD.MM.YYYY)
+		Until      string `json:"until" example:"2024-05-31"`                     // Only videos published on or before this day (YYYY-MM-DD or DD.MM.YYYY)
 	}
 
 	// ClipMoneyParsingAccountResponse represents the response structure
@@ -20,6 +23,7 @@ type (
 		Success bool                         `json:"success" example:"true"`    // Response success status
 		Message string                       `json:"message" example:"success"` // Response message
 		Data    []*models.ClipMoneyResultRow `json:"data"`                      // Parsed account data
+		Profile *models.AccountProfile       `json:"profile,omitempty"`         // Account snapshot: followers, posts, ER by followers (vk, instagram, youtube, tiktok)
 	}
 )
 
@@ -34,13 +38,13 @@ func NewClipMoneyParsingAccount(logger *slog.Logger, usecase *parsing_account.Us
 
 // ClipMoneyParsingAccount godoc
 // @Summary      Parses clips, reels, videos for an account
-// @Description  Parses clips for youtube, vk account, videos for tiktok and reels for instagram
+// @Description  Parses clips for youtube, vk account, videos for tiktok, reels (or the whole feed with full_feed) for instagram, posts for telegram channel and videos for rutube channel. For vk, instagram, youtube and tiktok the response also contains an account profile snapshot with followers count and ER by followers
 // @Tags         ClipMoney
 // @Accept       json
 // @Produce      json
 // @Param        request body ClipMoneyParsingAccountRequest true "Account URL to parse"
 // @Success      200  {object}  ClipMoneyParsingAccountResponse  "Successfully parsed account"
-// @Failure      400  {object}  ClipMoneyParsingAccountResponse  "Invalid request format or missing account_url"
+// @Failure      400  {object}  ClipMoneyParsingAccountResponse  "Invalid request format, missing account_url or invalid since/until"
 // @Failure      405  {object}  ClipMoneyParsingAccountResponse  "Method not allowed"
 // @Failure      500  {object}  ClipMoneyParsingAccountResponse  "Internal server error"
 // @Router       /clip_money/parsing_account [post]
@@ -75,8 +79,22 @@ func (h *ClipMoneyParsingAccount) ClipMoneyParsingAccount(w http.ResponseWriter,
 		return
 	}
 
-	data, err := h.usecase.ClipMoneyParseAccount(
+	window, err := models.ParseDateWindow(req.Since, req.Until)
+	if err != nil {
+		resp := ClipMoneyParsingAccountResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	data, profile, err := h.usecase.ClipMoneyParseAccount(
+		r.Context(),
 		req.AccountUrl,
+		req.FullFeed,
+		window,
 	)
 	if err != nil {
 		h.logger.Error("Failed to parse account data",
@@ -98,6 +116,7 @@ func (h *ClipMoneyParsingAccount) ClipMoneyParsingAccount(w http.ResponseWriter,
 		Success: true,
 		Message: "",
 		Data:    data,
+		Profile: profile,
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -34,7 +34,7 @@ func NewClipMoneyParsingUrl(logger *slog.Logger, usecase *parsing_urls.Usecase)
 
 // ClipMoneyParsingUrl godoc
 // @Summary      Parse video by URL
-// @Description  Parse video from tiktok, clip from youtube,vk or reel from instagram
+// @Description  Parse video from tiktok, clip from youtube,vk, reel from instagram, telegram channel post or rutube video
 // @Tags         ClipMoney
 // @Accept       json
 // @Produce      json
@@ -76,6 +76,7 @@ func (h *ClipMoneyParsingUrl) ClipMoneyParsingUrl(w http.ResponseWriter, r *http
 	}
 
 	data, err := h.usecase.ClipMoneyParseUrl(
+		r.Context(),
 		req.Url,
 	)
 	if err != nil {
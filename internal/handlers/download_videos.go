@@ -92,7 +92,7 @@ func (h *DownloadVideos) DownloadVideos(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	zipBytes, errors, err := h.usecase.DownloadVideos(req.Urls)
+	zipBytes, errors, err := h.usecase.DownloadVideos(r.Context(), req.Urls)
 	if err != nil {
 		h.logger.Error("Failed to download videos",
 			slog.String("err", err.Error()),
@@ -160,7 +160,7 @@ func (h *DownloadVideos) DownloadVideosGet(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	zipBytes, errors, err := h.usecase.DownloadVideos(urls)
+	zipBytes, errors, err := h.usecase.DownloadVideos(r.Context(), urls)
 	if err != nil {
 		h.logger.Error("Failed to download videos",
 			slog.String("err", err.Error()),
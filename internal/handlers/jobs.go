@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"inst_parser/internal/models"
+)
+
+type (
+	// JobResponse represents the response structure for a single job
+	JobResponse struct {
+		Success bool        `json:"success" example:"true"` // Operation success status
+		Message string      `json:"message" example:""`     // Response message
+		Data    *models.Job `json:"data"`                   // Job state
+	}
+
+	// JobsResponse represents the response structure for a list of jobs
+	JobsResponse struct {
+		Success bool          `json:"success" example:"true"` // Operation success status
+		Message string        `json:"message" example:""`     // Response message
+		Data    []*models.Job `json:"data"`                   // Jobs, newest first
+	}
+)
+
+type JobsProvider interface {
+	Job(id string) (*models.Job, bool)
+	Jobs(spreadsheetID string) []*models.Job
+	Cancel(id string) (*models.Job, error)
+	DeadLetter(spreadsheetID string) []*models.Job
+	Requeue(id string) (*models.Job, error)
+}
+
+type JobsHandler struct {
+	logger       *slog.Logger
+	jobsProvider JobsProvider
+}
+
+func NewJobsHandler(logger *slog.Logger, jobsProvider JobsProvider) *JobsHandler {
+	return &JobsHandler{
+		logger:       logger,
+		jobsProvider: jobsProvider,
+	}
+}
+
+// Job godoc
+// @Summary      Get queued job status
+// @Description  Returns state, timestamps, url totals and error summary of a queued parse
+// @Tags         Jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  JobResponse  "Job found"
+// @Failure      404  {object}  JobResponse  "Job not found"
+// @Failure      405  {object}  JobResponse  "Method not allowed"
+// @Router       /jobs/{id} [get]
+func (h *JobsHandler) Job(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, ok := h.jobsProvider.Job(r.PathValue("id"))
+	if !ok {
+		resp := JobResponse{
+			Success: false,
+			Message: "job not found",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	resp := JobResponse{
+		Success: true,
+		Data:    job,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Jobs godoc
+// @Summary      List queued jobs
+// @Description  Returns jobs locked on the spreadsheet (or all jobs if spreadsheet_id is empty), newest first
+// @Tags         Jobs
+// @Produce      json
+// @Param        spreadsheet_id  query     string  false  "Spreadsheet ID"
+// @Success      200  {object}  JobsResponse  "Jobs list"
+// @Failure      405  {object}  JobsResponse  "Method not allowed"
+// @Router       /jobs [get]
+func (h *JobsHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := JobsResponse{
+		Success: true,
+		Data:    h.jobsProvider.Jobs(r.URL.Query().Get("spreadsheet_id")),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CancelJob godoc
+// @Summary      Cancel queued job
+// @Description  Cancels a pending or running job. A running job stops, writes results collected so far and marks the progress sheet as cancelled
+// @Tags         Jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  JobResponse  "Cancellation accepted"
+// @Failure      404  {object}  JobResponse  "Job not found"
+// @Failure      405  {object}  JobResponse  "Method not allowed"
+// @Failure      409  {object}  JobResponse  "Job is already completed"
+// @Router       /jobs/{id} [delete]
+func (h *JobsHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, err := h.jobsProvider.Cancel(r.PathValue("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, models.ErrJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrJobNotActive):
+			status = http.StatusConflict
+		}
+
+		resp := JobResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	h.logger.Info("Job cancellation requested",
+		slog.String("job_id", job.ID),
+		slog.String("kind", job.Kind),
+	)
+
+	resp := JobResponse{
+		Success: true,
+		Data:    job,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeadLetterJobs godoc
+// @Summary      List dead-letter jobs
+// @Description  Returns jobs that exhausted their retries on transient errors, newest first
+// @Tags         Jobs
+// @Produce      json
+// @Param        spreadsheet_id  query     string  false  "Spreadsheet ID"
+// @Success      200  {object}  JobsResponse  "Dead-letter jobs"
+// @Failure      405  {object}  JobsResponse  "Method not allowed"
+// @Router       /jobs/dead_letter [get]
+func (h *JobsHandler) DeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := JobsResponse{
+		Success: true,
+		Data:    h.jobsProvider.DeadLetter(r.URL.Query().Get("spreadsheet_id")),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RequeueJob godoc
+// @Summary      Requeue failed job
+// @Description  Puts a failed or dead-letter job back into the queue under the same ID with a fresh retry budget
+// @Tags         Jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  JobResponse  "Job requeued"
+// @Failure      404  {object}  JobResponse  "Job not found"
+// @Failure      405  {object}  JobResponse  "Method not allowed"
+// @Failure      409  {object}  JobResponse  "Job is not failed or dead-letter"
+// @Failure      429  {object}  JobResponse  "Queue is full, see Retry-After"
+// @Failure      500  {object}  JobResponse  "Failed to requeue job"
+// @Router       /jobs/{id}/requeue [post]
+func (h *JobsHandler) RequeueJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, err := h.jobsProvider.Requeue(r.PathValue("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, models.ErrJobNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrJobNotRequeueable):
+			status = http.StatusConflict
+		case errors.Is(err, models.ErrQueueFull):
+			w.Header().Set("Retry-After", queueFullRetryAfter)
+			status = http.StatusTooManyRequests
+		}
+
+		resp := JobResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	h.logger.Info("Job requeued",
+		slog.String("job_id", job.ID),
+		slog.String("kind", job.Kind),
+	)
+
+	resp := JobResponse{
+		Success: true,
+		Data:    job,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
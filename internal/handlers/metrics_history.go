@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
+)
+
+// MetricsHistoryResponse represents the response structure for a video metrics series
+type MetricsHistoryResponse struct {
+	Success bool                 `json:"success" example:"true"` // Operation success status
+	Message string               `json:"message" example:""`     // Response message
+	Data    *models.MetricSeries `json:"data"`                   // Snapshots oldest first and delta between two of them
+}
+
+type MetricsHistoryProvider interface {
+	Series(key string) []*models.MetricSnapshot
+}
+
+type MetricsHistoryHandler struct {
+	logger          *slog.Logger
+	historyProvider MetricsHistoryProvider
+}
+
+func NewMetricsHistoryHandler(logger *slog.Logger, historyProvider MetricsHistoryProvider) *MetricsHistoryHandler {
+	return &MetricsHistoryHandler{
+		logger:          logger,
+		historyProvider: historyProvider,
+	}
+}
+
+// MetricsHistory godoc
+// @Summary      Get video metrics history
+// @Description  Returns every stored snapshot of a video (any form of its link) and the delta between the snapshots taken at from and to: the latest snapshot not after the moment is used. Without from and to the delta is between the first and the last snapshot
+// @Tags         Metrics
+// @Produce      json
+// @Param        url   query     string  true   "Video URL"
+// @Param        from  query     string  false  "RFC3339 time or day (YYYY-MM-DD, DD.MM.YYYY; start of day, Moscow)"
+// @Param        to    query     string  false  "RFC3339 time or day (YYYY-MM-DD, DD.MM.YYYY; end of day, Moscow)"
+// @Success      200  {object}  MetricsHistoryResponse  "Video series"
+// @Failure      400  {object}  MetricsHistoryResponse  "Missing url or invalid from/to"
+// @Failure      404  {object}  MetricsHistoryResponse  "No snapshots for the video"
+// @Failure      405  {object}  MetricsHistoryResponse  "Method not allowed"
+// @Router       /metrics_history [get]
+func (h *MetricsHistoryHandler) MetricsHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+
+	url := query.Get("url")
+	if url == "" {
+		resp := MetricsHistoryResponse{
+			Success: false,
+			Message: "url is required",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	from, err := parseHistoryTime(query.Get("from"), false)
+	if err != nil {
+		resp := MetricsHistoryResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	to, err := parseHistoryTime(query.Get("to"), true)
+	if err != nil {
+		resp := MetricsHistoryResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	snapshots := h.historyProvider.Series(models.UpsertKey(url))
+	if len(snapshots) == 0 {
+		resp := MetricsHistoryResponse{
+			Success: false,
+			Message: "no metrics history for the url",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	resp := MetricsHistoryResponse{
+		Success: true,
+		Data:    models.NewMetricSeries(url, snapshots, from, to),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// parseHistoryTime момент из запроса: RFC3339 или день — его начало либо, с endOfDay, конец
+func parseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := utils.ParseDay(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	return day, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -13,10 +14,19 @@ type (
 		SpreadsheetID string `json:"spreadsheet_id"`
 		SheetName     string `json:"sheet_name"`
 		IsSelected    bool   `json:"is_selected"`
+		// FullFeed Instagram-аккаунты парсятся по всей ленте: фото, карусели и reels
+		FullFeed bool `json:"full_feed"`
+		// Since, Until окно дат публикации, дни включительно: 2024-05-01 или 01.05.2024.
+		// Заменяет глубину из листа.
+		Since string `json:"since"`
+		Until string `json:"until"`
+		// Upsert обновлять строки роликов, уже записанных в лист аккаунтов, вместо добавления
+		Upsert bool `json:"upsert"`
 	}
 	ParsingAccountResponse struct {
 		Success bool   `json:"success"`
 		Message string `json:"message"`
+		JobID   string `json:"job_id,omitempty"`
 	}
 )
 
@@ -76,18 +86,32 @@ func (h *ParsingAccount) ParsingAccount(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	window, err := models.ParseDateWindow(req.Since, req.Until)
+	if err != nil {
+		resp := ParsingAccountResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
 	//go h.usecase.ParseAccount(
 	//	req.IsSelected,
 	//	req.SheetName,
 	//	req.SpreadsheetID,
 	//)
 
-	if err := h.queueProvider.Enqueue(models.QueueRequest{
+	jobID, err := h.queueProvider.Enqueue(models.JobKindParseAccount, models.SheetPayload{
 		SpreadsheetID: req.SpreadsheetID,
 		SheetName:     req.SheetName,
 		IsSelected:    req.IsSelected,
-		Type:          1,
-	}); err != nil {
+		FullFeed:      req.FullFeed,
+		Window:        window,
+		Upsert:        req.Upsert,
+	})
+	if err != nil {
 		h.logger.Error("failed to enqueue spreadsheet item",
 			slog.String("spreadsheet_id", req.SpreadsheetID),
 			slog.String("err", err.Error()),
@@ -98,7 +122,15 @@ func (h *ParsingAccount) ParsingAccount(w http.ResponseWriter, r *http.Request)
 			Message: "failed to enqueue spreadsheet item",
 		}
 
-		w.WriteHeader(http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrQueueFull) {
+			// очередь переполнена — клиент может повторить позже
+			w.Header().Set("Retry-After", queueFullRetryAfter)
+			resp.Message = err.Error()
+			status = http.StatusTooManyRequests
+		}
+
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(resp)
 		return
 	}
@@ -107,6 +139,7 @@ func (h *ParsingAccount) ParsingAccount(w http.ResponseWriter, r *http.Request)
 	resp := ParsingAccountResponse{
 		Success: true,
 		Message: "ParsingAccountRequest received successfully",
+		JobID:   jobID,
 	}
 
 	w.WriteHeader(http.StatusOK)
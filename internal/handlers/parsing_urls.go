@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"inst_parser/internal/models"
 	"log/slog"
 	"net/http"
@@ -11,15 +12,23 @@ type ParsingUrlsRequest struct {
 	SpreadsheetID string `json:"spreadsheet_id"`
 	SheetName     string `json:"sheet_name"`
 	IsSelected    bool   `json:"is_selected"`
+	// WriteBack обновить метрики в строках листа, откуда взяты ссылки, вместо добавления в DataTable
+	WriteBack bool `json:"write_back"`
+	// Upsert обновлять строки роликов, уже записанных в DataTable, вместо добавления
+	Upsert bool `json:"upsert"`
 }
 
 type ParsingUrlsResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	JobID   string `json:"job_id,omitempty"`
 }
 
+// queueFullRetryAfter через сколько секунд предлагаем повторить запрос при переполненной очереди
+const queueFullRetryAfter = "60"
+
 type QueueProvider interface {
-	Enqueue(req models.QueueRequest) error
+	Enqueue(kind string, payload any) (string, error)
 }
 
 type ParsingUrlsHandler struct {
@@ -84,12 +93,14 @@ func (h *ParsingUrlsHandler) ParsingUrls(w http.ResponseWriter, r *http.Request)
 	//	req.SpreadsheetID,
 	//)
 
-	if err := h.queueProvider.Enqueue(models.QueueRequest{
+	jobID, err := h.queueProvider.Enqueue(models.JobKindParseUrls, models.SheetPayload{
 		SpreadsheetID: req.SpreadsheetID,
 		SheetName:     req.SheetName,
 		IsSelected:    req.IsSelected,
-		Type:          0,
-	}); err != nil {
+		WriteBack:     req.WriteBack,
+		Upsert:        req.Upsert,
+	})
+	if err != nil {
 		h.logger.Error("failed to enqueue spreadsheet item",
 			slog.String("spreadsheet_id", req.SpreadsheetID),
 			slog.String("err", err.Error()),
@@ -100,7 +111,15 @@ func (h *ParsingUrlsHandler) ParsingUrls(w http.ResponseWriter, r *http.Request)
 			Message: "failed to enqueue spreadsheet item",
 		}
 
-		w.WriteHeader(http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrQueueFull) {
+			// очередь переполнена — клиент может повторить позже
+			w.Header().Set("Retry-After", queueFullRetryAfter)
+			resp.Message = err.Error()
+			status = http.StatusTooManyRequests
+		}
+
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(resp)
 		return
 	}
@@ -109,6 +128,7 @@ func (h *ParsingUrlsHandler) ParsingUrls(w http.ResponseWriter, r *http.Request)
 	resp := ParsingUrlsResponse{
 		Success: true,
 		Message: "ParsingUrlsRequest received successfully",
+		JobID:   jobID,
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"inst_parser/internal/models"
+)
+
+type (
+	// ScheduleRequest represents the request body for registering a schedule
+	ScheduleRequest struct {
+		SpreadsheetID string `json:"spreadsheet_id" example:"1A2B3C"`          // Spreadsheet ID
+		SheetName     string `json:"sheet_name" example:"Лист1"`               // Sheet name
+		IsSelected    bool   `json:"is_selected" example:"false"`              // Parse only selected rows
+		WriteBack     bool   `json:"write_back" example:"false"`               // parse_urls only: update metrics in the source rows
+		FullFeed      bool   `json:"full_feed" example:"false"`                // parse_account only: walk the whole Instagram feed, not only reels
+		Since         string `json:"since" example:"2024-05-01"`               // parse_account only: first publication day, inclusive (YYYY-MM-DD or DD.MM.YYYY)
+		Until         string `json:"until" example:"2024-05-31"`               // parse_account only: last publication day, inclusive
+		Upsert        bool   `json:"upsert" example:"false"`                   // Update rows of already written videos instead of appending result rows
+		Kind          string `json:"kind" example:"parse_urls"`                // parse_urls or parse_account
+		Spec          string `json:"spec" example:"daily 09:00 Europe/Moscow"` // "every 6 hours", "daily HH:MM [tz]" or 5-field cron
+	}
+
+	// ScheduleResponse represents the response structure for a single schedule
+	ScheduleResponse struct {
+		Success bool             `json:"success" example:"true"` // Operation success status
+		Message string           `json:"message" example:""`     // Response message
+		Data    *models.Schedule `json:"data"`                   // Schedule with last and next run
+	}
+
+	// SchedulesResponse represents the response structure for a list of schedules
+	SchedulesResponse struct {
+		Success bool               `json:"success" example:"true"` // Operation success status
+		Message string             `json:"message" example:""`     // Response message
+		Data    []*models.Schedule `json:"data"`                   // Schedules in creation order
+	}
+)
+
+type SchedulesProvider interface {
+	Add(schedule models.Schedule) (*models.Schedule, error)
+	Delete(id string) error
+	Schedule(id string) (*models.Schedule, bool)
+	Schedules(spreadsheetID string) []*models.Schedule
+}
+
+type SchedulesHandler struct {
+	logger            *slog.Logger
+	schedulesProvider SchedulesProvider
+}
+
+func NewSchedulesHandler(logger *slog.Logger, schedulesProvider SchedulesProvider) *SchedulesHandler {
+	return &SchedulesHandler{
+		logger:            logger,
+		schedulesProvider: schedulesProvider,
+	}
+}
+
+// CreateSchedule godoc
+// @Summary      Register recurring parse
+// @Description  Registers a spreadsheet/sheet/kind combination to be enqueued on a schedule: "every N minutes|hours|days", "daily HH:MM [timezone]" or 5-field cron with optional CRON_TZ= prefix
+// @Tags         Schedules
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ScheduleRequest  true  "Schedule"
+// @Success      200  {object}  ScheduleResponse  "Schedule registered"
+// @Failure      400  {object}  ScheduleResponse  "Invalid schedule"
+// @Failure      405  {object}  ScheduleResponse  "Method not allowed"
+// @Failure      500  {object}  ScheduleResponse  "Failed to save schedule"
+// @Router       /schedules [post]
+func (h *SchedulesHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp := ScheduleResponse{
+			Success: false,
+			Message: "Invalid JSON format",
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	window, err := models.ParseDateWindow(req.Since, req.Until)
+	if err != nil {
+		resp := ScheduleResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	schedule, err := h.schedulesProvider.Add(models.Schedule{
+		SpreadsheetID: req.SpreadsheetID,
+		SheetName:     req.SheetName,
+		IsSelected:    req.IsSelected,
+		WriteBack:     req.WriteBack,
+		FullFeed:      req.FullFeed,
+		Window:        window,
+		Upsert:        req.Upsert,
+		Kind:          req.Kind,
+		Spec:          req.Spec,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrInvalidSchedule) {
+			status = http.StatusBadRequest
+		} else {
+			h.logger.Error("failed to add schedule",
+				slog.String("spreadsheet_id", req.SpreadsheetID),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		resp := ScheduleResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	h.logger.Info("Schedule registered",
+		slog.String("schedule_id", schedule.ID),
+		slog.String("spreadsheet_id", schedule.SpreadsheetID),
+		slog.String("spec", schedule.Spec),
+	)
+
+	resp := ScheduleResponse{
+		Success: true,
+		Data:    schedule,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Schedules godoc
+// @Summary      List schedules
+// @Description  Returns schedules of the spreadsheet (or all schedules if spreadsheet_id is empty) with last and next run times
+// @Tags         Schedules
+// @Produce      json
+// @Param        spreadsheet_id  query     string  false  "Spreadsheet ID"
+// @Success      200  {object}  SchedulesResponse  "Schedules list"
+// @Failure      405  {object}  SchedulesResponse  "Method not allowed"
+// @Router       /schedules [get]
+func (h *SchedulesHandler) Schedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := SchedulesResponse{
+		Success: true,
+		Data:    h.schedulesProvider.Schedules(r.URL.Query().Get("spreadsheet_id")),
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Schedule godoc
+// @Summary      Get schedule
+// @Description  Returns schedule with last and next run times
+// @Tags         Schedules
+// @Produce      json
+// @Param        id   path      string  true  "Schedule ID"
+// @Success      200  {object}  ScheduleResponse  "Schedule found"
+// @Failure      404  {object}  ScheduleResponse  "Schedule not found"
+// @Failure      405  {object}  ScheduleResponse  "Method not allowed"
+// @Router       /schedules/{id} [get]
+func (h *SchedulesHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	schedule, ok := h.schedulesProvider.Schedule(r.PathValue("id"))
+	if !ok {
+		resp := ScheduleResponse{
+			Success: false,
+			Message: models.ErrScheduleNotFound.Error(),
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	resp := ScheduleResponse{
+		Success: true,
+		Data:    schedule,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteSchedule godoc
+// @Summary      Delete schedule
+// @Description  Removes schedule. Jobs already enqueued by it are not cancelled
+// @Tags         Schedules
+// @Produce      json
+// @Param        id   path      string  true  "Schedule ID"
+// @Success      200  {object}  ScheduleResponse  "Schedule deleted"
+// @Failure      404  {object}  ScheduleResponse  "Schedule not found"
+// @Failure      405  {object}  ScheduleResponse  "Method not allowed"
+// @Failure      500  {object}  ScheduleResponse  "Failed to save schedules"
+// @Router       /schedules/{id} [delete]
+func (h *SchedulesHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.schedulesProvider.Delete(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrScheduleNotFound) {
+			status = http.StatusNotFound
+		} else {
+			h.logger.Error("failed to delete schedule",
+				slog.String("schedule_id", id),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		resp := ScheduleResponse{
+			Success: false,
+			Message: err.Error(),
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	h.logger.Info("Schedule deleted", slog.String("schedule_id", id))
+
+	resp := ScheduleResponse{
+		Success: true,
+		Message: "schedule deleted",
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
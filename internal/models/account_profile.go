@@ -0,0 +1,63 @@
+package models
+
+import (
+	"inst_parser/internal/utils"
+)
+
+// AccountProfile снимок профиля аккаунта на момент парсинга
+type AccountProfile struct {
+	AccountUrl  string      `json:"account_url"`
+	Platform    ParsingType `json:"platform"`
+	DisplayName string      `json:"display_name"`
+	Followers   int64       `json:"followers"`
+	Following   int64       `json:"following"`
+	MediaCount  int64       `json:"media_count"` // всего публикаций в профиле
+	Verified    bool        `json:"verified"`
+	AvatarURL   string      `json:"avatar_url"`
+	// ERFollowers средняя вовлечённость спарсенных публикаций к числу подписчиков
+	ERFollowers string `json:"er_followers"`
+	ParsingDate string `json:"parsing_date"`
+}
+
+// AccountSummaryHeaders заголовки листа сводки по аккаунтам
+var AccountSummaryHeaders = []interface{}{
+	"Ссылка на аккаунт",
+	"Платформа",
+	"Название",
+	"Подписчики",
+	"Подписки",
+	"Публикаций",
+	"Верификация",
+	"Аватар",
+	"ER по подписчикам",
+	"Дата парсинга",
+}
+
+// SetEngagement считает ER по подписчикам по спарсенным публикациям аккаунта
+func (p *AccountProfile) SetEngagement(rows []*ClipMoneyResultRow) {
+	var engagement, posts int64
+	for _, row := range rows {
+		if row == nil || row.URL == "" {
+			continue
+		}
+		engagement += row.Likes + row.Comments + row.Shares
+		posts++
+	}
+
+	p.ERFollowers = utils.GetERByFollowers(engagement, posts, p.Followers)
+}
+
+func (p *AccountProfile) ToInterface() []interface{} {
+	return []interface{}{
+		p.AccountUrl,
+		string(p.Platform),
+		p.DisplayName,
+		p.Followers,
+		p.Following,
+		p.MediaCount,
+		p.Verified,
+		p.AvatarURL,
+		p.ERFollowers,
+		p.ParsingDate,
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAccountProfile_SetEngagement(t *testing.T) {
+	type args struct {
+		followers int64
+		rows      []*ClipMoneyResultRow
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "average over posts",
+			args: args{followers: 1000, rows: []*ClipMoneyResultRow{
+				{URL: "https://vk.com/clip-1_1", Likes: 10, Comments: 5, Shares: 5},
+				{URL: "https://vk.com/clip-1_2", Likes: 20, Comments: 0, Shares: 0},
+			}},
+			want: "2.00%",
+		},
+		{
+			name: "skips empty rows",
+			args: args{followers: 100, rows: []*ClipMoneyResultRow{
+				{URL: "https://vk.com/clip-1_1", Likes: 10},
+				{},
+				nil,
+			}},
+			want: "10.00%",
+		},
+		{name: "hidden followers", args: args{rows: []*ClipMoneyResultRow{{URL: "u", Likes: 10}}}, want: "0"},
+		{name: "no posts", args: args{followers: 100}, want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &AccountProfile{Followers: tt.args.followers}
+			p.SetEngagement(tt.args.rows)
+			if p.ERFollowers != tt.want {
+				t.Errorf("ERFollowers = %v, want %v", p.ERFollowers, tt.want)
+			}
+		})
+	}
+}
+
+func TestYouTubeChannel_ToProfile(t *testing.T) {
+	var channel YouTubeChannel
+	data := `{"snippet":{"title":"Channel","thumbnails":{"default":{"url":"https://yt/default.jpg"},"high":{"url":"https://yt/high.jpg"}}},
+		"statistics":{"subscriberCount":"1500","videoCount":"42"}}`
+	if err := json.Unmarshal([]byte(data), &channel); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	got := channel.ToProfile()
+	if got.DisplayName != "Channel" || got.Followers != 1500 || got.MediaCount != 42 || got.AvatarURL != "https://yt/high.jpg" {
+		t.Errorf("ToProfile() = %+v", got)
+	}
+}
+
+func TestTikTokSearchUser_ToProfile(t *testing.T) {
+	var user TikTokSearchUser
+	data := `{"user":{"id":"1","uniqueId":"name","nickname":"Name","avatarThumb":"https://tt/a.jpg","verified":true},
+		"stats":{"followerCount":200,"followingCount":10,"videoCount":7}}`
+	if err := json.Unmarshal([]byte(data), &user); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	got := user.ToProfile()
+	if got.DisplayName != "Name" || got.Followers != 200 || got.Following != 10 || got.MediaCount != 7 || !got.Verified {
+		t.Errorf("ToProfile() = %+v", got)
+	}
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// CanonicalURL ссылка на ролик, приведённая к платформе и стабильному ID контента
+type CanonicalURL struct {
+	Platform ParsingType
+	ID       string
+	URL      string // нормализованная ссылка, по ней идёт запрос к провайдеру
+}
+
+// Key ключ контента: разные формы ссылки на один ролик дают один ключ
+func (c CanonicalURL) Key() string {
+	return string(c.Platform) + ":" + c.ID
+}
+
+var (
+	instagramPathRe = regexp.MustCompile(`^/(?:[^/]+/)?(p|reels?|tv)/([A-Za-z0-9_-]+)`)
+	tiktokPathRe    = regexp.MustCompile(`^/@([^/]+)/(video|photo)/(\d+)`)
+	vkContentRe     = regexp.MustCompile(`(clip|wall|video)(-?\d+_\d+)`)
+	youtubeIDRe     = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+)
+
+// Canonicalize приводит ссылку на ролик к каноническому виду:
+// instagram.com/p/X и /reel/X, m.tiktok.com, vk.ru, vkvideo.ru и vk.com, любые
+// ссылки на ролик YouTube, посты t.me, ролики Rutube, ссылки с трекинговыми параметрами. false — ссылка не
+// распознана как ссылка на ролик поддерживаемой платформы.
+func Canonicalize(raw string) (CanonicalURL, bool) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return CanonicalURL{}, false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	host = strings.TrimPrefix(host, "m.")
+	host = strings.TrimPrefix(host, "music.")
+
+	switch host {
+	case "instagram.com":
+		m := instagramPathRe.FindStringSubmatch(u.Path)
+		if m == nil {
+			return CanonicalURL{}, false
+		}
+		// ключ — shortcode, вид ссылки сохраняем: фото и карусели открываются только по /p/
+		kind := m[1]
+		if kind == "reels" {
+			kind = "reel"
+		}
+		return CanonicalURL{
+			Platform: InstagramParsingType,
+			ID:       m[2],
+			URL:      "https://www.instagram.com/" + kind + "/" + m[2] + "/",
+		}, true
+
+	case "tiktok.com":
+		m := tiktokPathRe.FindStringSubmatch(u.Path)
+		if m == nil {
+			return CanonicalURL{}, false
+		}
+		return CanonicalURL{
+			Platform: TiktokParsingType,
+			ID:       m[3],
+			URL:      "https://www.tiktok.com/@" + m[1] + "/" + m[2] + "/" + m[3],
+		}, true
+
+	case "vk.com", "vk.ru", "vkvideo.ru":
+		// ролик бывает и в пути (vk.com/clip-1_2), и в параметрах (vk.com/clips?z=clip-1_2)
+		m := vkContentRe.FindStringSubmatch(u.Path)
+		if m == nil {
+			m = vkContentRe.FindStringSubmatch(u.RawQuery)
+		}
+		if m == nil {
+			return CanonicalURL{}, false
+		}
+		return CanonicalURL{
+			Platform: VKGroupParsingType,
+			ID:       m[1] + m[2],
+			URL:      "https://vk.com/" + m[1] + m[2],
+		}, true
+
+	case "youtube.com", "youtu.be":
+		id := youtubeVideoID(host, u)
+		if !youtubeIDRe.MatchString(id) {
+			return CanonicalURL{}, false
+		}
+		return CanonicalURL{
+			Platform: YoutubeParsingType,
+			ID:       id,
+			URL:      "https://www.youtube.com/watch?v=" + id,
+		}, true
+
+	case "rutube.ru":
+		id, ok := ExtractRutubeVideoID(raw)
+		if !ok {
+			return CanonicalURL{}, false
+		}
+		return CanonicalURL{
+			Platform: RutubeParsingType,
+			ID:       id,
+			URL:      "https://rutube.ru/video/" + id + "/",
+		}, true
+
+	case "t.me", "telegram.me":
+		channel, id, ok := ParseTelegramPostURL(raw)
+		if !ok {
+			return CanonicalURL{}, false
+		}
+		return CanonicalURL{
+			Platform: TelegramParsingType,
+			// имя канала в ссылках встречается в разном регистре
+			ID:  fmt.Sprintf("%s/%d", strings.ToLower(channel), id),
+			URL: fmt.Sprintf("https://t.me/%s/%d", channel, id),
+		}, true
+	}
+
+	return CanonicalURL{}, false
+}
+
+func youtubeVideoID(host string, u *url.URL) string {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	if host == "youtu.be" {
+		return segments[0]
+	}
+
+	switch segments[0] {
+	case "watch":
+		return u.Query().Get("v")
+	case "shorts", "live", "embed", "v":
+		if len(segments) > 1 {
+			return segments[1]
+		}
+	}
+
+	return ""
+}
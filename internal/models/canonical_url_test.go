@@ -0,0 +1,111 @@
+package models
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantKey string
+		wantURL string
+		wantOk  bool
+	}{
+		{
+			name:    "instagram post",
+			args:    args{raw: "https://www.instagram.com/p/DAbc_12-x/?igsh=MWx2"},
+			wantKey: "instagram:DAbc_12-x",
+			wantURL: "https://www.instagram.com/p/DAbc_12-x/",
+			wantOk:  true,
+		},
+		{
+			name:    "instagram reel with user",
+			args:    args{raw: "instagram.com/someone/reel/DAbc_12-x"},
+			wantKey: "instagram:DAbc_12-x",
+			wantURL: "https://www.instagram.com/reel/DAbc_12-x/",
+			wantOk:  true,
+		},
+		{
+			name:    "instagram reels tab link",
+			args:    args{raw: "https://www.instagram.com/reels/DAbc_12-x/"},
+			wantKey: "instagram:DAbc_12-x",
+			wantURL: "https://www.instagram.com/reel/DAbc_12-x/",
+			wantOk:  true,
+		},
+		{
+			name:    "instagram igtv",
+			args:    args{raw: "https://instagram.com/tv/DAbc_12-x"},
+			wantKey: "instagram:DAbc_12-x",
+			wantURL: "https://www.instagram.com/tv/DAbc_12-x/",
+			wantOk:  true,
+		},
+		{
+			name:    "tiktok mobile",
+			args:    args{raw: "https://m.tiktok.com/@user.name/video/7412345678901234567?is_from_webapp=1&sender_device=pc"},
+			wantKey: "tiktok:7412345678901234567",
+			wantURL: "https://www.tiktok.com/@user.name/video/7412345678901234567",
+			wantOk:  true,
+		},
+		{
+			name:    "vk.ru clip",
+			args:    args{raw: "https://vk.ru/clip-220754053_456239596?utm_source=share"},
+			wantKey: "vk:clip-220754053_456239596",
+			wantURL: "https://vk.com/clip-220754053_456239596",
+			wantOk:  true,
+		},
+		{
+			name:    "vk clip in query",
+			args:    args{raw: "https://vk.com/clips/group?z=clip-220754053_456239596"},
+			wantKey: "vk:clip-220754053_456239596",
+			wantURL: "https://vk.com/clip-220754053_456239596",
+			wantOk:  true,
+		},
+		{
+			name:    "vk wall",
+			args:    args{raw: "https://m.vk.com/wall-1_2"},
+			wantKey: "vk:wall-1_2",
+			wantURL: "https://vk.com/wall-1_2",
+			wantOk:  true,
+		},
+		{
+			name:    "youtu.be",
+			args:    args{raw: "https://youtu.be/dQw4w9WgXcQ?si=abc"},
+			wantKey: "youtube:dQw4w9WgXcQ",
+			wantURL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantOk:  true,
+		},
+		{
+			name:    "youtube watch",
+			args:    args{raw: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=10"},
+			wantKey: "youtube:dQw4w9WgXcQ",
+			wantURL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantOk:  true,
+		},
+		{
+			name:   "account link",
+			args:   args{raw: "https://www.instagram.com/ollaserebro"},
+			wantOk: false,
+		},
+		{
+			name:   "unknown host",
+			args:   args{raw: "https://example.com/p/abc"},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Canonicalize(tt.args.raw)
+			if ok != tt.wantOk {
+				t.Fatalf("Canonicalize() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if got.Key() != tt.wantKey || got.URL != tt.wantURL {
+				t.Errorf("Canonicalize() = %v, %v, want %v, %v", got.Key(), got.URL, tt.wantKey, tt.wantURL)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"inst_parser/internal/constants"
 	"inst_parser/internal/utils"
 )
 
@@ -24,6 +25,63 @@ type ClipMoneyResultRow struct {
 	ErID           string `json:"er_id"`
 	INN            string `json:"inn"`
 	AdvertiserName string `json:"advertiser_name"`
+	ViewsGain24h   string `json:"views_gain_24h,omitempty"` // прирост просмотров за сутки по истории метрик
+	ViewsGain7d    string `json:"views_gain_7d,omitempty"`  // прирост просмотров за неделю
+}
+
+// Колонки AccountTable. Первые AccountRowWidth у платформ свои, самая широкая строка —
+// клипы вк с ERID, ИНН и рекламодателем. Дальше колонки, общие для всех платформ.
+const (
+	AccountRowWidth           = 14
+	AccountContentTypeColumn  = AccountRowWidth
+	AccountDurationColumn     = AccountRowWidth + 1
+	AccountViewsGain24hColumn = AccountRowWidth + 2
+	AccountViewsGain7dColumn  = AccountRowWidth + 3
+)
+
+// accountRow дополняет строку платформы пустыми ячейками до общих колонок
+// и пишет в них тип контента и длительность
+func accountRow(values []interface{}, contentType, duration string) []interface{} {
+	row := padRow(values, AccountContentTypeColumn)
+	return append(row, contentType, duration)
+}
+
+// padRow копия строки, дополненная пустыми ячейками до width
+func padRow(values []interface{}, width int) []interface{} {
+	row := make([]interface{}, max(len(values), width), max(len(values), width)+4)
+	copy(row, values)
+	for i := len(values); i < width; i++ {
+		row[i] = ""
+	}
+
+	return row
+}
+
+// AccountRowsWithViewsGain дополняет строки AccountTable до колонок прироста и дописывает
+// прирост просмотров за сутки и неделю публикации с той же ссылкой, чтобы колонки
+// прироста у всех платформ были на одном месте
+func AccountRowsWithViewsGain(values [][]interface{}, rows []*ClipMoneyResultRow) [][]interface{} {
+	byKey := make(map[string]*ClipMoneyResultRow, len(rows))
+	for _, row := range rows {
+		if row != nil && row.URL != "" {
+			byKey[UpsertKey(row.URL)] = row
+		}
+	}
+
+	result := make([][]interface{}, 0, len(values))
+	for _, value := range values {
+		extended := padRow(value, AccountViewsGain24hColumn)
+
+		var gain24h, gain7d string
+		if len(value) > constants.AccountTableKeyColumn {
+			if row, ok := byKey[UpsertKey(fmt.Sprint(value[constants.AccountTableKeyColumn]))]; ok {
+				gain24h, gain7d = row.ViewsGain24h, row.ViewsGain7d
+			}
+		}
+		result = append(result, append(extended, gain24h, gain7d))
+	}
+
+	return result
 }
 
 // todo remove
@@ -132,7 +190,7 @@ func ClipMoneyResultRowFromYoutubeShortInfoApiResponse(data []*YoutubeShortInfoA
 
 		result[i] = &ClipMoneyResultRow{
 			AccountUrl:  accountUrl,
-			URL:         fmt.Sprintf("https://www.youtube.com/shorts/%s", data[i].ID),
+			URL:         data[i].VideoURL(),
 			Description: fmt.Sprintf("%s.%s", data[i].Title, data[i].Description),
 			Views:       int64(views),
 			Likes:       int64(likes),
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"inst_parser/internal/utils"
+)
+
+// DateWindow окно дат публикации для парсинга аккаунта; нулевая граница — без ограничения
+type DateWindow struct {
+	Since time.Time `json:"since,omitzero"` // включительно
+	Until time.Time `json:"until,omitzero"` // не включительно
+}
+
+// ParseDateWindow окно из дат запроса: since и until — дни включительно, пустая дата — без границы
+func ParseDateWindow(since, until string) (DateWindow, error) {
+	var window DateWindow
+
+	if strings.TrimSpace(since) != "" {
+		day, err := utils.ParseDay(since)
+		if err != nil {
+			return DateWindow{}, fmt.Errorf("since: %w", err)
+		}
+		window.Since = day
+	}
+
+	if strings.TrimSpace(until) != "" {
+		day, err := utils.ParseDay(until)
+		if err != nil {
+			return DateWindow{}, fmt.Errorf("until: %w", err)
+		}
+		window.Until = day.AddDate(0, 0, 1)
+	}
+
+	if !window.Since.IsZero() && !window.Until.IsZero() && !window.Since.Before(window.Until) {
+		return DateWindow{}, fmt.Errorf("since %s is after until %s", since, until)
+	}
+
+	return window, nil
+}
+
+// LastDays окно за последние days дней, включая сегодняшний
+func LastDays(days int, now time.Time) DateWindow {
+	return DateWindow{Since: utils.StartOfDay(now).AddDate(0, 0, -(days - 1))}
+}
+
+func (w DateWindow) IsZero() bool {
+	return w.Since.IsZero() && w.Until.IsZero()
+}
+
+// Contains true, если публикация попадает в окно. Без окна подходит любая,
+// с окном публикация без даты не подходит.
+func (w DateWindow) Contains(published time.Time) bool {
+	if w.IsZero() {
+		return true
+	}
+	if published.IsZero() {
+		return false
+	}
+
+	return (w.Since.IsZero() || !published.Before(w.Since)) &&
+		(w.Until.IsZero() || published.Before(w.Until))
+}
+
+// Passed true, если публикация старше начала окна: ленты идут от новых к старым,
+// дальше по ленте подходящих публикаций не будет
+func (w DateWindow) Passed(published time.Time) bool {
+	return !w.Since.IsZero() && !published.IsZero() && published.Before(w.Since)
+}
+
+// String окно для ключей и логов: 2024-05-01..2024-05-31
+func (w DateWindow) String() string {
+	var since, until string
+	if !w.Since.IsZero() {
+		since = w.Since.Format(time.DateOnly)
+	}
+	if !w.Until.IsZero() {
+		until = w.Until.AddDate(0, 0, -1).Format(time.DateOnly)
+	}
+
+	return since + ".." + until
+}
+
+// depthDaysRe глубина в днях: 30d, 30д
+var depthDaysRe = regexp.MustCompile(`^(\d+)\s*(?:d|д)$`)
+
+// ParseAccountDepth значение колонки "Глубина": число публикаций или число дней ("30d").
+// false — значение не распознано.
+func ParseAccountDepth(value string, now time.Time) (int, DateWindow, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if m := depthDaysRe.FindStringSubmatch(value); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil || days <= 0 {
+			return 0, DateWindow{}, false
+		}
+		return 0, LastDays(days, now), true
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, DateWindow{}, false
+	}
+
+	return count, DateWindow{}, true
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"inst_parser/internal/utils"
+)
+
+func TestParseDateWindow(t *testing.T) {
+	type args struct {
+		since string
+		until string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "month", args: args{since: "2024-05-01", until: "2024-05-31"}, want: "2024-05-01..2024-05-31"},
+		{name: "russian format", args: args{since: "01.05.2024", until: "31.05.2024"}, want: "2024-05-01..2024-05-31"},
+		{name: "open end", args: args{since: "2024-05-01"}, want: "2024-05-01.."},
+		{name: "empty", args: args{}, want: ".."},
+		{name: "one day", args: args{since: "2024-05-01", until: "2024-05-01"}, want: "2024-05-01..2024-05-01"},
+		{name: "reversed", args: args{since: "2024-05-31", until: "2024-05-01"}, wantErr: true},
+		{name: "invalid date", args: args{since: "май"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateWindow(tt.args.since, tt.args.until)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseDateWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateWindow_Contains(t *testing.T) {
+	window, err := ParseDateWindow("2024-05-01", "2024-05-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moscow := utils.Moscow()
+
+	tests := []struct {
+		name       string
+		published  time.Time
+		want       bool
+		wantPassed bool
+	}{
+		{name: "first day", published: time.Date(2024, 5, 1, 0, 0, 0, 0, moscow), want: true},
+		{name: "last day evening", published: time.Date(2024, 5, 31, 23, 59, 0, 0, moscow), want: true},
+		{name: "next day", published: time.Date(2024, 6, 1, 0, 0, 0, 0, moscow), want: false},
+		{name: "before window", published: time.Date(2024, 4, 30, 23, 59, 0, 0, moscow), want: false, wantPassed: true},
+		{name: "unknown date", published: time.Time{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := window.Contains(tt.published); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+			if got := window.Passed(tt.published); got != tt.wantPassed {
+				t.Errorf("Passed() = %v, want %v", got, tt.wantPassed)
+			}
+		})
+	}
+
+	if !(DateWindow{}).Contains(time.Time{}) {
+		t.Error("empty window must contain any publication")
+	}
+}
+
+func TestParseAccountDepth(t *testing.T) {
+	now := time.Date(2024, 5, 31, 15, 0, 0, 0, utils.Moscow())
+
+	tests := []struct {
+		name       string
+		value      string
+		wantCount  int
+		wantWindow string
+		wantOk     bool
+	}{
+		{name: "count", value: "30", wantCount: 30, wantWindow: "..", wantOk: true},
+		{name: "days", value: "30d", wantWindow: "2024-05-02..", wantOk: true},
+		{name: "days in russian", value: "7 д", wantWindow: "2024-05-25..", wantOk: true},
+		{name: "today only", value: "1d", wantWindow: "2024-05-31..", wantOk: true},
+		{name: "empty", value: "", wantWindow: "..", wantOk: false},
+		{name: "zero days", value: "0d", wantWindow: "..", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, window, ok := ParseAccountDepth(tt.value, now)
+			if count != tt.wantCount || window.String() != tt.wantWindow || ok != tt.wantOk {
+				t.Errorf("ParseAccountDepth() = %v, %v, %v, want %v, %v, %v",
+					count, window, ok, tt.wantCount, tt.wantWindow, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// MarkTransient помечает ошибку как временную (ErrTransient), если она
+// похожа на сбой сети или перегрузку API: такую задачу очередь повторит.
+// Остальные ошибки возвращаются как есть.
+func MarkTransient(err error) error {
+	if err == nil || !IsTransient(err) || errors.Is(err, ErrTransient) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrTransient, err)
+}
+
+// IsTransient true для сетевых ошибок, таймаутов и ответов Google API 429/5xx
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrTransient) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
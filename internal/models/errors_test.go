@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestMarkTransient(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "sheets rate limit",
+			args: args{err: fmt.Errorf("failed to get values: %w", &googleapi.Error{Code: 429})},
+			want: true,
+		},
+		{
+			name: "sheets unavailable",
+			args: args{err: &googleapi.Error{Code: 503}},
+			want: true,
+		},
+		{
+			name: "sheet not found",
+			args: args{err: &googleapi.Error{Code: 404}},
+			want: false,
+		},
+		{
+			name: "network error",
+			args: args{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}},
+			want: true,
+		},
+		{
+			name: "timeout",
+			args: args{err: fmt.Errorf("request: %w", context.DeadlineExceeded)},
+			want: true,
+		},
+		{
+			name: "columns not found",
+			args: args{err: errors.New("url column not found")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarkTransient(tt.args.err)
+			if isTransient := errors.Is(got, ErrTransient); isTransient != tt.want {
+				t.Errorf("MarkTransient() transient = %v, want %v", isTransient, tt.want)
+			}
+			if !errors.Is(got, tt.args.err) {
+				t.Errorf("MarkTransient() lost original error: %v", got)
+			}
+		})
+	}
+}
@@ -14,15 +14,24 @@ type ResultRowUrl struct {
 	Likes          int64  // Лайки
 	Comments       int64  // Комментарии
 	Shares         int64  // Репосты
+	NoShares       bool   // платформа не отдаёт репосты: ячейка репостов пустая, а не 0
 	ER             string // ER (likes+shares+comments)/views*100
 	Virality       string // Виральность (shared/views)*100
 	ParsingDate    string // Дата обновления
 	PublishDate    string // Дата публикации
 	VideoUrls      []string
-	OwnerUrl       string // Ссылка на канал
-	ErID           string // айди рекламы, только для вк
-	INN            string // инн, только для вк
-	AdvertiserName string // имя рекламодателя, только для вк
+	MediaUrls      []string  // файлы публикации для скачивания, у карусели по одному на элемент
+	OwnerUrl       string    // Ссылка на канал
+	ErID           string    // айди рекламы, только для вк
+	INN            string    // инн, только для вк
+	AdvertiserName string    // имя рекламодателя, только для вк
+	Status         UrlStatus // итог парсинга ссылки
+	Reason         string    // причина, если статус не ok
+	CanonicalURL   string    // ссылка, по которой шёл запрос: каноническая или раскрытая короткая
+	ContentType    string    // вид ролика: short, video, live у YouTube; reel, video, photo, carousel у Instagram
+	Duration       string    // длительность ролика, только для YouTube и Rutube
+	ViewsGain24h   string    // прирост просмотров за сутки по истории метрик, пусто без истории
+	ViewsGain7d    string    // прирост просмотров за неделю по истории метрик
 }
 
 type ResultRowAccount struct {
@@ -0,0 +1,160 @@
+package models
+
+import "fmt"
+
+// InstagramMediaType вид публикации в Instagram
+type InstagramMediaType string
+
+const (
+	InstagramMediaReel     InstagramMediaType = "reel"
+	InstagramMediaVideo    InstagramMediaType = "video"
+	InstagramMediaPhoto    InstagramMediaType = "photo"
+	InstagramMediaCarousel InstagramMediaType = "carousel"
+)
+
+// значения media_type в ответах Instagram
+const (
+	instagramMediaTypeVideo    = 2
+	instagramMediaTypeCarousel = 8
+)
+
+// instagramProductClips product_type роликов из раздела Reels
+const instagramProductClips = "clips"
+
+// InstagramMediaTypeOf вид публикации по media_type и product_type из ответа API
+func InstagramMediaTypeOf(mediaType int, productType string) InstagramMediaType {
+	switch mediaType {
+	case instagramMediaTypeCarousel:
+		return InstagramMediaCarousel
+	case instagramMediaTypeVideo:
+		// без product_type видео считаем роликом Reels, как и раньше
+		if productType == instagramProductClips || productType == "" {
+			return InstagramMediaReel
+		}
+		return InstagramMediaVideo
+	default:
+		return InstagramMediaPhoto
+	}
+}
+
+// InstagramMediaURL ссылка на публикацию: reels открываются по /reel/, остальное по /p/
+func InstagramMediaURL(code string, mediaType InstagramMediaType) string {
+	if mediaType == InstagramMediaReel {
+		return fmt.Sprintf("https://www.instagram.com/reel/%s/", code)
+	}
+
+	return fmt.Sprintf("https://www.instagram.com/p/%s/", code)
+}
+
+// InstagramImageVersions варианты картинки разного размера
+type InstagramImageVersions struct {
+	Candidates []InstagramImageCandidate `json:"candidates"`
+}
+
+type InstagramImageCandidate struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	URL    string `json:"url"`
+}
+
+// InstagramCarouselMedia элемент карусели: фото или видео
+type InstagramCarouselMedia struct {
+	ID             string                  `json:"id"`
+	MediaType      int                     `json:"media_type"`
+	VideoVersions  []MediaInfoVideoVersion `json:"video_versions,omitempty"`
+	ImageVersions2 InstagramImageVersions  `json:"image_versions2"`
+}
+
+// MediaURL ссылка на файл элемента карусели в лучшем качестве
+func (c InstagramCarouselMedia) MediaURL() string {
+	if c.MediaType == instagramMediaTypeVideo {
+		if url := bestVideoURL(c.VideoVersions); url != "" {
+			return url
+		}
+	}
+
+	return bestImageURL(c.ImageVersions2.Candidates)
+}
+
+// Type вид публикации
+func (d *RealTimeScraperMediaInfoData) Type() InstagramMediaType {
+	return InstagramMediaTypeOf(d.MediaType, d.ProductType)
+}
+
+// Views просмотры: у reels это ig_play_count, у видео из ленты play_count или view_count.
+// Для фото и каруселей Instagram просмотры не отдаёт.
+func (d *RealTimeScraperMediaInfoData) Views() int64 {
+	switch {
+	case d.IgPlayCount > 0:
+		return d.IgPlayCount
+	case d.PlayCount != nil && *d.PlayCount > 0:
+		return *d.PlayCount
+	case d.ViewCount != nil:
+		return *d.ViewCount
+	}
+
+	return 0
+}
+
+// MediaURLs ссылки на файлы публикации, по одной на фото или видео; у карусели — все элементы
+func (d *RealTimeScraperMediaInfoData) MediaURLs() []string {
+	var urls []string
+
+	switch d.Type() {
+	case InstagramMediaCarousel:
+		for _, child := range d.CarouselMedia {
+			if url := child.MediaURL(); url != "" {
+				urls = append(urls, url)
+			}
+		}
+	case InstagramMediaPhoto:
+		if url := bestImageURL(d.ImageVersions2.Candidates); url != "" {
+			urls = append(urls, url)
+		}
+	default:
+		if url := bestVideoURL(d.VideoVersions); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+// Type вид публикации из ленты аккаунта
+func (m *InstagramMedia) Type() InstagramMediaType {
+	return InstagramMediaTypeOf(m.MediaType, m.ProductType)
+}
+
+// Views просмотры публикации из ленты, см. RealTimeScraperMediaInfoData.Views
+func (m *InstagramMedia) Views() int {
+	switch {
+	case m.IgPlayCount > 0:
+		return m.IgPlayCount
+	case m.PlayCount > 0:
+		return m.PlayCount
+	}
+
+	return m.ViewCount
+}
+
+func bestVideoURL(versions []MediaInfoVideoVersion) string {
+	var best MediaInfoVideoVersion
+	for _, v := range versions {
+		if v.URL != "" && (best.URL == "" || v.Width*v.Height > best.Width*best.Height) {
+			best = v
+		}
+	}
+
+	return best.URL
+}
+
+func bestImageURL(candidates []InstagramImageCandidate) string {
+	var best InstagramImageCandidate
+	for _, c := range candidates {
+		if c.URL != "" && (best.URL == "" || c.Width*c.Height > best.Width*best.Height) {
+			best = c
+		}
+	}
+
+	return best.URL
+}
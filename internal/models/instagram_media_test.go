@@ -0,0 +1,147 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInstagramMediaTypeOf(t *testing.T) {
+	type args struct {
+		mediaType   int
+		productType string
+	}
+	tests := []struct {
+		name string
+		args args
+		want InstagramMediaType
+	}{
+		{name: "reel", args: args{mediaType: 2, productType: "clips"}, want: InstagramMediaReel},
+		{name: "video without product type", args: args{mediaType: 2}, want: InstagramMediaReel},
+		{name: "feed video", args: args{mediaType: 2, productType: "feed"}, want: InstagramMediaVideo},
+		{name: "photo", args: args{mediaType: 1, productType: "feed"}, want: InstagramMediaPhoto},
+		{name: "carousel", args: args{mediaType: 8, productType: "carousel_container"}, want: InstagramMediaCarousel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InstagramMediaTypeOf(tt.args.mediaType, tt.args.productType); got != tt.want {
+				t.Errorf("InstagramMediaTypeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessInstagramResponse(t *testing.T) {
+	type args struct {
+		item string
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantType      InstagramMediaType
+		wantViews     int64
+		wantMediaUrls []string
+	}{
+		{
+			name: "reel",
+			args: args{item: `{"media_type":2,"product_type":"clips","ig_play_count":1000,"play_count":900,"like_count":10,
+				"video_versions":[{"width":480,"height":854,"url":"https://cdn/low.mp4"},{"width":720,"height":1280,"url":"https://cdn/high.mp4"}]}`},
+			wantType:      InstagramMediaReel,
+			wantViews:     1000,
+			wantMediaUrls: []string{"https://cdn/high.mp4"},
+		},
+		{
+			name: "feed video counts play_count",
+			args: args{item: `{"media_type":2,"product_type":"feed","play_count":500,"view_count":300,
+				"video_versions":[{"width":720,"height":1280,"url":"https://cdn/video.mp4"}]}`},
+			wantType:      InstagramMediaVideo,
+			wantViews:     500,
+			wantMediaUrls: []string{"https://cdn/video.mp4"},
+		},
+		{
+			name: "photo",
+			args: args{item: `{"media_type":1,"product_type":"feed","like_count":10,
+				"image_versions2":{"candidates":[{"width":320,"height":320,"url":"https://cdn/small.jpg"},{"width":1080,"height":1080,"url":"https://cdn/big.jpg"}]}}`},
+			wantType:      InstagramMediaPhoto,
+			wantViews:     0,
+			wantMediaUrls: []string{"https://cdn/big.jpg"},
+		},
+		{
+			name: "carousel exposes every child",
+			args: args{item: `{"media_type":8,"product_type":"carousel_container","carousel_media_count":2,"carousel_media":[
+				{"id":"1","media_type":1,"image_versions2":{"candidates":[{"width":1080,"height":1080,"url":"https://cdn/1.jpg"}]}},
+				{"id":"2","media_type":2,"video_versions":[{"width":720,"height":1280,"url":"https://cdn/2.mp4"}],
+					"image_versions2":{"candidates":[{"width":720,"height":1280,"url":"https://cdn/2.jpg"}]}}]}`},
+			wantType:      InstagramMediaCarousel,
+			wantViews:     0,
+			wantMediaUrls: []string{"https://cdn/1.jpg", "https://cdn/2.mp4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp RealTimeScraperMediaInfoResponse
+			if err := json.Unmarshal([]byte(`{"data":{"items":[`+tt.args.item+`]},"status":"ok"}`), &resp); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			got, err := ProcessInstagramResponse(&resp, "https://www.instagram.com/p/ABC/", true)
+			if err != nil {
+				t.Fatalf("ProcessInstagramResponse() error = %v", err)
+			}
+			if got.ContentType != string(tt.wantType) {
+				t.Errorf("ContentType = %v, want %v", got.ContentType, tt.wantType)
+			}
+			if got.Views != tt.wantViews {
+				t.Errorf("Views = %v, want %v", got.Views, tt.wantViews)
+			}
+			if !reflect.DeepEqual(got.MediaUrls, tt.wantMediaUrls) {
+				t.Errorf("MediaUrls = %v, want %v", got.MediaUrls, tt.wantMediaUrls)
+			}
+		})
+	}
+}
+
+func TestInstagramReelItem_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantURL string
+	}{
+		{name: "user_reels item", data: `{"media":{"code":"R1","media_type":2,"product_type":"clips"}}`, wantURL: "https://www.instagram.com/reel/R1/"},
+		{name: "user_posts item", data: `{"code":"P1","media_type":8}`, wantURL: "https://www.instagram.com/p/P1/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item InstagramReelItem
+			if err := json.Unmarshal([]byte(tt.data), &item); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			if got := ProcessInstagramReelResponse(&item.Media, "").URL; got != tt.wantURL {
+				t.Errorf("URL = %v, want %v", got, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestInstagramReelInfoToInterface(t *testing.T) {
+	rows := InstagramReelInfoToInterface([]*InstagramReelInfo{{
+		AccountURL:  "https://www.instagram.com/acc/",
+		URL:         "https://www.instagram.com/p/ABC/",
+		ContentType: string(InstagramMediaCarousel),
+	}})
+
+	// колонки ERID, ИНН и рекламодателя вк остаются пустыми, тип контента — в общей колонке
+	row := rows[0]
+	if len(row) != AccountDurationColumn+1 {
+		t.Fatalf("row len = %d, want %d", len(row), AccountDurationColumn+1)
+	}
+	for i := 11; i < AccountRowWidth; i++ {
+		if row[i] != "" {
+			t.Errorf("row[%d] = %v, want empty", i, row[i])
+		}
+	}
+	if row[AccountContentTypeColumn] != string(InstagramMediaCarousel) {
+		t.Errorf("content type = %v, want %v", row[AccountContentTypeColumn], InstagramMediaCarousel)
+	}
+}
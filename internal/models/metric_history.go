@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetricSnapshot метрики ролика на момент парсинга
+type MetricSnapshot struct {
+	Key      string      `json:"key"` // ключ контента, см. UpsertKey
+	URL      string      `json:"url"`
+	Platform ParsingType `json:"platform"`
+	At       time.Time   `json:"at"`
+	Views    int64       `json:"views"`
+	Likes    int64       `json:"likes"`
+	Comments int64       `json:"comments"`
+	Shares   int64       `json:"shares"`
+}
+
+// SnapshotFromResultRow снимок по результату парсинга ссылки; неудачный парсинг снимка не даёт
+func SnapshotFromResultRow(row *ResultRowUrl, at time.Time) *MetricSnapshot {
+	if row == nil || row.URL == "" || row.Failed() {
+		return nil
+	}
+
+	url := row.URL
+	if row.CanonicalURL != "" {
+		url = row.CanonicalURL
+	}
+
+	return &MetricSnapshot{
+		Key:      UpsertKey(url),
+		URL:      url,
+		Platform: ParsingTypeByUrl(url),
+		At:       at,
+		Views:    row.Views,
+		Likes:    row.Likes,
+		Comments: row.Comments,
+		Shares:   row.Shares,
+	}
+}
+
+// SnapshotFromClipMoneyRow снимок публикации аккаунта, nil для строки без ссылки
+func SnapshotFromClipMoneyRow(row *ClipMoneyResultRow, at time.Time) *MetricSnapshot {
+	if row == nil || row.URL == "" {
+		return nil
+	}
+
+	return &MetricSnapshot{
+		Key:      UpsertKey(row.URL),
+		URL:      row.URL,
+		Platform: ParsingTypeByUrl(row.URL),
+		At:       at,
+		Views:    row.Views,
+		Likes:    row.Likes,
+		Comments: row.Comments,
+		Shares:   row.Shares,
+	}
+}
+
+// MetricDelta прирост метрик между двумя снимками
+type MetricDelta struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Views    int64     `json:"views"`
+	Likes    int64     `json:"likes"`
+	Comments int64     `json:"comments"`
+	Shares   int64     `json:"shares"`
+}
+
+func DeltaBetween(from, to *MetricSnapshot) *MetricDelta {
+	return &MetricDelta{
+		From:     from.At,
+		To:       to.At,
+		Views:    to.Views - from.Views,
+		Likes:    to.Likes - from.Likes,
+		Comments: to.Comments - from.Comments,
+		Shares:   to.Shares - from.Shares,
+	}
+}
+
+// SnapshotAt последний снимок не позже at; series отсортирована по времени. nil — снимков до at нет.
+func SnapshotAt(series []*MetricSnapshot, at time.Time) *MetricSnapshot {
+	var found *MetricSnapshot
+	for _, snapshot := range series {
+		if snapshot.At.After(at) {
+			break
+		}
+		found = snapshot
+	}
+
+	return found
+}
+
+// ViewsGain прирост просмотров current за period: "+120". Пусто, если снимка
+// period назад ещё нет.
+func ViewsGain(series []*MetricSnapshot, current *MetricSnapshot, period time.Duration) string {
+	if current == nil {
+		return ""
+	}
+
+	past := SnapshotAt(series, current.At.Add(-period))
+	if past == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%+d", current.Views-past.Views)
+}
+
+// MetricSeries история метрик ролика и прирост между двумя снимками
+type MetricSeries struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Snapshots []*MetricSnapshot `json:"snapshots"`
+	Delta     *MetricDelta      `json:"delta,omitempty"` // nil, если снимков меньше двух
+}
+
+// NewMetricSeries серия со сравнением снимков на моменты from и to: берётся последний
+// снимок не позже момента, до начала истории — первый снимок. Нулевые from и to —
+// первый и последний снимки.
+func NewMetricSeries(url string, snapshots []*MetricSnapshot, from, to time.Time) *MetricSeries {
+	series := &MetricSeries{
+		Key:       UpsertKey(url),
+		URL:       url,
+		Snapshots: snapshots,
+	}
+	if len(snapshots) < 2 {
+		return series
+	}
+
+	fromSnapshot, toSnapshot := snapshots[0], snapshots[len(snapshots)-1]
+	if !from.IsZero() {
+		if snapshot := SnapshotAt(snapshots, from); snapshot != nil {
+			fromSnapshot = snapshot
+		}
+	}
+	if !to.IsZero() {
+		if snapshot := SnapshotAt(snapshots, to); snapshot != nil {
+			toSnapshot = snapshot
+		} else {
+			toSnapshot = snapshots[0]
+		}
+	}
+
+	series.Delta = DeltaBetween(fromSnapshot, toSnapshot)
+
+	return series
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewMetricSeries(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	snapshots := []*MetricSnapshot{
+		{At: start, Views: 100, Likes: 10},
+		{At: start.Add(24 * time.Hour), Views: 250, Likes: 12},
+		{At: start.Add(7 * 24 * time.Hour), Views: 1000, Likes: 40},
+	}
+
+	type args struct {
+		from time.Time
+		to   time.Time
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantViews int64
+		wantLikes int64
+	}{
+		{name: "whole series", wantViews: 900, wantLikes: 30},
+		{name: "between snapshots", args: args{from: start.Add(time.Hour), to: start.Add(48 * time.Hour)}, wantViews: 150, wantLikes: 2},
+		{name: "from before history", args: args{from: start.Add(-time.Hour)}, wantViews: 900, wantLikes: 30},
+		{name: "to before history", args: args{to: start.Add(-time.Hour)}, wantViews: 0, wantLikes: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMetricSeries("https://www.instagram.com/reel/ABC/", snapshots, tt.args.from, tt.args.to)
+			if got.Delta == nil {
+				t.Fatal("Delta = nil")
+			}
+			if got.Delta.Views != tt.wantViews || got.Delta.Likes != tt.wantLikes {
+				t.Errorf("Delta = %+v, want views %d likes %d", got.Delta, tt.wantViews, tt.wantLikes)
+			}
+		})
+	}
+
+	if got := NewMetricSeries("u", snapshots[:1], time.Time{}, time.Time{}); got.Delta != nil {
+		t.Errorf("Delta = %+v, want nil for a single snapshot", got.Delta)
+	}
+}
+
+func TestViewsGain(t *testing.T) {
+	now := time.Date(2024, 5, 8, 9, 0, 0, 0, time.UTC)
+	series := []*MetricSnapshot{
+		{At: now.Add(-8 * 24 * time.Hour), Views: 10},
+		{At: now.Add(-30 * time.Hour), Views: 400},
+		{At: now.Add(-2 * time.Hour), Views: 900},
+	}
+	current := &MetricSnapshot{At: now, Views: 1000}
+
+	if got := ViewsGain(series, current, 24*time.Hour); got != "+600" {
+		t.Errorf("ViewsGain(24h) = %v, want +600", got)
+	}
+	if got := ViewsGain(series, current, 7*24*time.Hour); got != "+990" {
+		t.Errorf("ViewsGain(7d) = %v, want +990", got)
+	}
+	if got := ViewsGain(series[2:], current, 24*time.Hour); got != "" {
+		t.Errorf("ViewsGain() = %v, want empty without history", got)
+	}
+}
+
+func TestSnapshotFromResultRow(t *testing.T) {
+	at := time.Now()
+
+	got := SnapshotFromResultRow(&ResultRowUrl{
+		URL:          "https://instagram.com/p/ABC/?igsh=1",
+		CanonicalURL: "https://www.instagram.com/reel/ABC/",
+		Views:        5,
+		Status:       UrlStatusOK,
+	}, at)
+	if got == nil || got.Key != UpsertKey("https://www.instagram.com/p/ABC/") || got.Views != 5 {
+		t.Errorf("SnapshotFromResultRow() = %+v", got)
+	}
+
+	if got = SnapshotFromResultRow(&ResultRowUrl{URL: "https://vk.com/clip-1_2", Status: UrlStatusNotFound}, at); got != nil {
+		t.Errorf("SnapshotFromResultRow() = %+v, want nil for failed row", got)
+	}
+}
+
+func TestAccountRowsWithViewsGain(t *testing.T) {
+	reels := InstagramReelInfoToInterface([]*InstagramReelInfo{
+		{AccountURL: "https://www.instagram.com/acc/", URL: "https://www.instagram.com/reel/A1/"},
+		{AccountURL: "https://www.instagram.com/acc/", URL: "https://www.instagram.com/reel/A2/"},
+	})
+	rows := []*ClipMoneyResultRow{
+		{URL: "https://www.instagram.com/reel/A1/", ViewsGain24h: "+10", ViewsGain7d: "+70"},
+		{URL: "https://www.instagram.com/reel/A2/"},
+	}
+
+	got := AccountRowsWithViewsGain(reels, rows)
+	if len(got) != 2 {
+		t.Fatalf("AccountRowsWithViewsGain() len = %d, want 2", len(got))
+	}
+	for i, row := range got {
+		if len(row) != AccountViewsGain7dColumn+1 {
+			t.Errorf("row %d len = %d, want %d", i, len(row), AccountViewsGain7dColumn+1)
+		}
+		if row[1] != reels[i][1] || row[AccountContentTypeColumn] != reels[i][AccountContentTypeColumn] {
+			t.Errorf("row %d = %v, want sheet row padded with empty cells", i, row)
+		}
+	}
+	if gains := got[0][AccountViewsGain24hColumn:]; gains[0] != "+10" || gains[1] != "+70" {
+		t.Errorf("row 0 gains = %v, want [+10 +70]", gains)
+	}
+	if gains := got[1][AccountViewsGain24hColumn:]; gains[0] != "" || gains[1] != "" {
+		t.Errorf("row 1 gains = %v, want empty without history", gains)
+	}
+}
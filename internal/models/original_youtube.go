@@ -5,6 +5,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"inst_parser/internal/utils"
 )
@@ -20,6 +21,36 @@ type YoutubeShortInfoApiResponse struct {
 	CommentCount  string `json:"commentCount"`
 	ShareCount    int    `json:"shareCount,omitempty"`
 	AccountURL    string
+	// ContentType шортс, обычное видео или трансляция
+	ContentType YoutubeContentType `json:"contentType,omitempty"`
+	Duration    time.Duration      `json:"duration,omitempty"`
+}
+
+// YoutubeContentType вид ролика на YouTube
+type YoutubeContentType string
+
+const (
+	YoutubeContentShort YoutubeContentType = "short"
+	YoutubeContentVideo YoutubeContentType = "video"
+	YoutubeContentLive  YoutubeContentType = "live"
+)
+
+// VideoURL ссылка на ролик в форме, соответствующей его виду
+func (y YoutubeShortInfoApiResponse) VideoURL() string {
+	if y.ContentType == YoutubeContentShort {
+		return fmt.Sprintf("https://www.youtube.com/shorts/%s", y.ID)
+	}
+
+	return fmt.Sprintf("https://www.youtube.com/watch?v=%s", y.ID)
+}
+
+// FormattedDuration длительность для таблицы, пустая для идущей трансляции
+func (y YoutubeShortInfoApiResponse) FormattedDuration() string {
+	if y.Duration <= 0 {
+		return ""
+	}
+
+	return utils.FormatDuration(y.Duration)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////
@@ -33,9 +64,28 @@ type VideosAPIResponse struct {
 }
 
 type VideoItem struct {
-	ID         string     `json:"id"`
-	Snippet    Snippet    `json:"snippet"`
-	Statistics Statistics `json:"statistics"`
+	ID                   string                `json:"id"`
+	Snippet              Snippet               `json:"snippet"`
+	Statistics           Statistics            `json:"statistics"`
+	ContentDetails       VideoContentDetails   `json:"contentDetails"`
+	LiveStreamingDetails *LiveStreamingDetails `json:"liveStreamingDetails,omitempty"`
+}
+
+type VideoContentDetails struct {
+	Duration string `json:"duration"` // ISO 8601, например PT1M5S
+}
+
+// LiveStreamingDetails есть только у трансляций, в том числе завершённых
+type LiveStreamingDetails struct {
+	ActualStartTime string `json:"actualStartTime"`
+	ActualEndTime   string `json:"actualEndTime"`
+}
+
+// IsLive true для идущих, запланированных и завершённых трансляций
+func (v VideoItem) IsLive() bool {
+	return v.LiveStreamingDetails != nil ||
+		v.Snippet.LiveBroadcastContent == "live" ||
+		v.Snippet.LiveBroadcastContent == "upcoming"
 }
 
 // VideoInfo содержит полную информацию о видео
@@ -55,6 +105,8 @@ type Snippet struct {
 	Description string `json:"description"`
 	PublishedAt string `json:"publishedAt"`
 	ChannelId   string `json:"channelId"`
+	// LiveBroadcastContent none, live или upcoming
+	LiveBroadcastContent string `json:"liveBroadcastContent"`
 }
 
 type Statistics struct {
@@ -80,6 +132,39 @@ type YouTubeChannel struct {
 			Uploads string `json:"uploads"`
 		} `json:"relatedPlaylists"`
 	} `json:"contentDetails"`
+	Snippet struct {
+		Title      string `json:"title"`
+		Thumbnails map[string]struct {
+			URL string `json:"url"`
+		} `json:"thumbnails"`
+	} `json:"snippet"`
+	Statistics struct {
+		SubscriberCount string `json:"subscriberCount"`
+		VideoCount      string `json:"videoCount"`
+	} `json:"statistics"`
+}
+
+// ToProfile профиль канала. Подписок и отметки верификации Data API не отдаёт,
+// скрытое число подписчиков приходит пустым.
+func (c *YouTubeChannel) ToProfile() *AccountProfile {
+	followers, _ := strconv.ParseInt(c.Statistics.SubscriberCount, 10, 64)
+	mediaCount, _ := strconv.ParseInt(c.Statistics.VideoCount, 10, 64)
+
+	var avatar string
+	for _, size := range []string{"high", "medium", "default"} {
+		if thumbnail, ok := c.Snippet.Thumbnails[size]; ok && thumbnail.URL != "" {
+			avatar = thumbnail.URL
+			break
+		}
+	}
+
+	return &AccountProfile{
+		Platform:    YoutubeParsingType,
+		DisplayName: c.Snippet.Title,
+		Followers:   followers,
+		MediaCount:  mediaCount,
+		AvatarURL:   avatar,
+	}
 }
 
 type PlaylistItemsResponse struct {
@@ -147,6 +232,8 @@ func (y YoutubeShortInfoApiResponse) ToResultRow(originalUrl string) *ResultRowU
 		Virality:    utils.GetVirality(int64(shares), int64(views)),
 		ParsingDate: utils.ParsingDate(),
 		PublishDate: utils.FormatParsingDate(publishDate),
+		ContentType: string(y.ContentType),
+		Duration:    y.FormattedDuration(),
 	}
 
 	return result
@@ -174,9 +261,10 @@ func (y YoutubeShortInfoApiResponse) ToInterface(accountURL string) []interface{
 		publishDate = y.PublishedDate
 	}
 
-	return []interface{}{
+	// тип и длительность — в общих колонках AccountTable, ERID и ИНН вк не занимаем
+	return accountRow([]interface{}{
 		accountURL,
-		fmt.Sprintf("https://www.youtube.com/shorts/%s", y.ID),
+		y.VideoURL(),
 		fmt.Sprintf("%s.%s", y.Title, y.Description),
 		int64(views),
 		int64(likes),
@@ -186,7 +274,7 @@ func (y YoutubeShortInfoApiResponse) ToInterface(accountURL string) []interface{
 		utils.GetVirality(int64(shares), int64(views)),
 		utils.ParsingDate(),
 		publishDate,
-	}
+	}, string(y.ContentType), y.FormattedDuration())
 
 }
 
@@ -229,3 +317,14 @@ func YoutubeShortInfoApiResponseToInterface(data []*YoutubeShortInfoApiResponse,
 
 	return values
 }
+
+// ExtractYouTubeVideoID ID ролика из ссылки любого вида: shorts, watch?v=, youtu.be,
+// live, embed, m.youtube.com и music.youtube.com
+func ExtractYouTubeVideoID(url string) (string, bool) {
+	canonical, ok := Canonicalize(url)
+	if !ok || canonical.Platform != YoutubeParsingType {
+		return "", false
+	}
+
+	return canonical.ID, true
+}
@@ -1,6 +1,9 @@
 package models
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestExtractYouTubeShortsID(t *testing.T) {
 	type args struct {
@@ -57,3 +60,118 @@ func TestExtractYouTubeShortsID(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractYouTubeVideoID(t *testing.T) {
+	type args struct {
+		url string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  string
+		want1 bool
+	}{
+		{name: "shorts", args: args{url: "https://youtube.com/shorts/5CHd6h1-Zps?si=SJx8dwcBw88NrL9j"}, want: "5CHd6h1-Zps", want1: true},
+		{name: "watch", args: args{url: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42s"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "youtu.be", args: args{url: "https://youtu.be/dQw4w9WgXcQ?si=abc"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "mobile", args: args{url: "https://m.youtube.com/watch?v=dQw4w9WgXcQ"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "music", args: args{url: "https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RD"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "live", args: args{url: "https://www.youtube.com/live/dQw4w9WgXcQ?feature=share"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "embed", args: args{url: "https://www.youtube.com/embed/dQw4w9WgXcQ"}, want: "dQw4w9WgXcQ", want1: true},
+		{name: "channel", args: args{url: "https://www.youtube.com/@mnogadetna"}, want: "", want1: false},
+		{name: "not youtube", args: args{url: "https://vk.com/clip-1_2"}, want: "", want1: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := ExtractYouTubeVideoID(tt.args.url)
+			if got != tt.want {
+				t.Errorf("ExtractYouTubeVideoID() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("ExtractYouTubeVideoID() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestYoutubeShortInfoApiResponse_ToResultRow(t *testing.T) {
+	tests := []struct {
+		name         string
+		info         YoutubeShortInfoApiResponse
+		wantURL      string
+		wantDuration string
+	}{
+		{
+			name:         "short",
+			info:         YoutubeShortInfoApiResponse{ID: "5CHd6h1-Zps", ContentType: YoutubeContentShort, Duration: 45 * time.Second},
+			wantURL:      "https://www.youtube.com/shorts/5CHd6h1-Zps",
+			wantDuration: "0:45",
+		},
+		{
+			name:         "video",
+			info:         YoutubeShortInfoApiResponse{ID: "dQw4w9WgXcQ", ContentType: YoutubeContentVideo, Duration: 213 * time.Second},
+			wantURL:      "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantDuration: "3:33",
+		},
+		{
+			name:         "live without duration",
+			info:         YoutubeShortInfoApiResponse{ID: "dQw4w9WgXcQ", ContentType: YoutubeContentLive},
+			wantURL:      "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantDuration: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.VideoURL(); got != tt.wantURL {
+				t.Errorf("VideoURL() = %v, want %v", got, tt.wantURL)
+			}
+
+			row := tt.info.ToResultRow(tt.wantURL)
+			if row.ContentType != string(tt.info.ContentType) {
+				t.Errorf("ToResultRow().ContentType = %v, want %v", row.ContentType, tt.info.ContentType)
+			}
+			if row.Duration != tt.wantDuration {
+				t.Errorf("ToResultRow().Duration = %v, want %v", row.Duration, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestVideoItem_IsLive(t *testing.T) {
+	tests := []struct {
+		name string
+		item VideoItem
+		want bool
+	}{
+		{name: "regular", item: VideoItem{Snippet: Snippet{LiveBroadcastContent: "none"}}, want: false},
+		{name: "live now", item: VideoItem{Snippet: Snippet{LiveBroadcastContent: "live"}}, want: true},
+		{name: "upcoming", item: VideoItem{Snippet: Snippet{LiveBroadcastContent: "upcoming"}}, want: true},
+		{name: "finished stream", item: VideoItem{LiveStreamingDetails: &LiveStreamingDetails{ActualEndTime: "2024-01-01T00:00:00Z"}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.IsLive(); got != tt.want {
+				t.Errorf("IsLive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYoutubeShortInfoApiResponse_ToInterface(t *testing.T) {
+	row := YoutubeShortInfoApiResponse{
+		ID:           "dQw4w9WgXcQ",
+		ViewCount:    "10",
+		CommentCount: "1",
+		ContentType:  YoutubeContentShort,
+	}.ToInterface("https://www.youtube.com/@acc")
+
+	if len(row) != AccountDurationColumn+1 {
+		t.Fatalf("row len = %d, want %d", len(row), AccountDurationColumn+1)
+	}
+	if row[11] != "" || row[12] != "" {
+		t.Errorf("row[11:13] = %v, want empty VK ERID and INN columns", row[11:13])
+	}
+	if row[AccountContentTypeColumn] != string(YoutubeContentShort) {
+		t.Errorf("content type = %v, want %v", row[AccountContentTypeColumn], YoutubeContentShort)
+	}
+}
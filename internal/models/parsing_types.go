@@ -11,12 +11,15 @@ const (
 	YoutubeParsingType   ParsingType = "youtube"
 	TiktokParsingType    ParsingType = "tiktok"
 	TelegramParsingType  ParsingType = "telegram"
+	RutubeParsingType    ParsingType = "rutube"
 	UnknownParsingType   ParsingType = "unknown"
 )
 
 func IsAvailableByParsingType(url string, parsingTypes []ParsingType) bool {
 	for _, parsingType := range parsingTypes {
-		if strings.Contains(url, string(parsingType)) {
+		// в ссылках t.me названия платформы нет
+		if strings.Contains(url, string(parsingType)) ||
+			parsingType == TelegramParsingType && ParsingTypeByUrl(url) == TelegramParsingType {
 			return true
 		}
 	}
@@ -32,7 +35,7 @@ func ParsingTypeByUrl(url string) ParsingType {
 		return VKGroupParsingType
 	}
 
-	if strings.Contains(urlLower, "vk.ru") {
+	if strings.Contains(urlLower, "vk.ru") || strings.Contains(urlLower, "vkvideo.ru") {
 		return VKGroupParsingType
 	}
 
@@ -41,8 +44,8 @@ func ParsingTypeByUrl(url string) ParsingType {
 		return InstagramParsingType
 	}
 
-	// Проверяем, содержит ли URL домен youtube.com
-	if strings.Contains(urlLower, "youtube.com") {
+	// Проверяем, содержит ли URL домен youtube.com или youtu.be
+	if strings.Contains(urlLower, "youtube.com") || strings.Contains(urlLower, "youtu.be") {
 		return YoutubeParsingType
 	}
 
@@ -50,5 +53,14 @@ func ParsingTypeByUrl(url string) ParsingType {
 		return TiktokParsingType
 	}
 
+	if strings.Contains(urlLower, "rutube.ru") {
+		return RutubeParsingType
+	}
+
+	if strings.HasPrefix(urlLower, "t.me/") || strings.Contains(urlLower, "//t.me/") ||
+		strings.Contains(urlLower, "telegram.me/") {
+		return TelegramParsingType
+	}
+
 	return UnknownParsingType
 }
@@ -1,8 +1,187 @@
 package models
 
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// QueueRequest задача очереди: вид задачи и его параметры в JSON
 type QueueRequest struct {
-	SpreadsheetID string
-	SheetName     string
-	IsSelected    bool
-	Type          int // 0 = urls, 1 = account
+	ID      string          `json:"id"`
+	Kind    string          `json:"kind"`
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+	// LockKey задачи с одинаковым ключом выполняются по очереди, пустой — без блокировки
+	LockKey string `json:"lock_key,omitempty"`
+	// DedupKey ожидающие задачи вида с одинаковым ключом схлопываются
+	DedupKey string `json:"dedup_key,omitempty"`
+}
+
+// виды задач очереди
+const (
+	JobKindParseUrls    = "parse_urls"
+	JobKindParseAccount = "parse_account"
+)
+
+// SheetPayload параметры парсинга листа таблицы
+type SheetPayload struct {
+	SpreadsheetID string `json:"spreadsheet_id"`
+	SheetName     string `json:"sheet_name"`
+	IsSelected    bool   `json:"is_selected"`
+	// WriteBack только для parse_urls: метрики пишутся в строку, откуда взята ссылка, а не в DataTable
+	WriteBack bool `json:"write_back,omitempty"`
+	// FullFeed только для parse_account: Instagram-аккаунты парсятся по всей ленте, а не только по reels
+	FullFeed bool `json:"full_feed,omitempty"`
+	// Window только для parse_account: окно дат публикации вместо глубины из листа
+	Window DateWindow `json:"window,omitzero"`
+	// Upsert строки уже записанных роликов обновляются по канонической ссылке;
+	// по умолчанию строки результата добавляются в конец листа
+	Upsert bool `json:"upsert,omitempty"`
+}
+
+func (p SheetPayload) Validate() error {
+	if p.SpreadsheetID == "" || p.SheetName == "" {
+		return errors.New("spreadsheet_id and sheet_name are required")
+	}
+
+	return nil
+}
+
+// LockKey парсинги одной таблицы не выполняются одновременно
+func (p SheetPayload) LockKey() string {
+	return p.SpreadsheetID
+}
+
+// DedupKey ожидающие парсинги одного листа схлопываются в одну задачу.
+// Парсинги с записью в исходные строки и в DataTable, по всей ленте и только по reels,
+// с обновлением и с добавлением строк не схлопываются: результат разный.
+func (p SheetPayload) DedupKey() string {
+	key := p.SpreadsheetID + "/" + p.SheetName
+	if p.WriteBack {
+		key += "#write_back"
+	}
+	if p.FullFeed {
+		key += "#full_feed"
+	}
+	if !p.Window.IsZero() {
+		key += "#" + p.Window.String()
+	}
+	if p.Upsert {
+		key += "#upsert"
+	}
+
+	return key
+}
+
+// Merge объединяет ожидающий парсинг с повторным запросом:
+// полный парсинг листа покрывает парсинг выделенных строк
+func (p SheetPayload) Merge(incoming SheetPayload) SheetPayload {
+	p.IsSelected = p.IsSelected && incoming.IsSelected
+	return p
+}
+
+// IsSheetJobKind true для видов задач, парсящих лист таблицы
+func IsSheetJobKind(kind string) bool {
+	return kind == JobKindParseUrls || kind == JobKindParseAccount
+}
+
+type JobState string
+
+const (
+	JobStatePending   JobState = "pending"
+	JobStateRunning   JobState = "running"
+	JobStateFinished  JobState = "finished"
+	JobStateFailed    JobState = "failed"
+	JobStateCancelled JobState = "cancelled"
+	// JobStateInterrupted задача была прервана остановкой сервиса
+	JobStateInterrupted JobState = "interrupted"
+	// JobStateRetrying задача упала на временной ошибке и ждёт повтора до RetryAt
+	JobStateRetrying JobState = "retrying"
+	// JobStateDeadLetter повторы исчерпаны, задачу можно перезапустить через API
+	JobStateDeadLetter JobState = "dead_letter"
+)
+
+var (
+	ErrJobNotFound  = errors.New("job not found")
+	ErrJobNotActive = errors.New("job is already completed")
+	ErrJobCancelled = errors.New("job cancelled")
+	ErrShutdown     = errors.New("service is shutting down")
+	// ErrTransient временная ошибка (сеть, перегрузка API): задача будет повторена
+	ErrTransient         = errors.New("transient error")
+	ErrJobNotRequeueable = errors.New("only failed or dead-letter jobs can be requeued")
+	ErrQueueFull         = errors.New("queue is full")
+	ErrUnknownJobKind    = errors.New("unknown job kind")
+	ErrInvalidPayload    = errors.New("invalid job payload")
+)
+
+// Job состояние задачи из очереди, отдаётся через /jobs
+type Job struct {
+	QueueRequest
+	State       JobState   `json:"state"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	TotalUrls   int        `json:"total_urls"`
+	Processed   int        `json:"processed"`
+	ErrorsCount int        `json:"errors_count"`
+	Errors      []string   `json:"errors,omitempty"`   // первые maxJobErrors ошибок
+	Restarts    int        `json:"restarts,omitempty"` // сколько раз задача перезапускалась после рестарта
+	Attempts    int        `json:"attempts"`           // сколько раз задача выполнялась с последней постановки
+	RetryAt     *time.Time `json:"retry_at,omitempty"` // когда задача в retrying вернётся в очередь
+	// Statuses сколько ссылок записано с каждым статусом, сводка по итогам задачи
+	Statuses map[UrlStatus]int `json:"statuses,omitempty"`
+}
+
+const maxJobErrors = 50
+
+// IsActive true, пока задача ждёт в очереди, выполняется или ждёт повтора
+func (j *Job) IsActive() bool {
+	return j.State == JobStatePending || j.State == JobStateRunning || j.State == JobStateRetrying
+}
+
+// AddError добавляет ошибку в сводку задачи, храня не более maxJobErrors сообщений
+func (j *Job) AddError(msg string) {
+	j.ErrorsCount++
+	if len(j.Errors) < maxJobErrors {
+		j.Errors = append(j.Errors, msg)
+	}
+}
+
+// ResetSummary сбрасывает ошибки и статусы прошлого запуска: повтор, перезапуск
+// и возврат в очередь выполняют задачу заново, и сводка собирается с нуля
+func (j *Job) ResetSummary() {
+	j.ErrorsCount = 0
+	j.Errors = nil
+	j.Statuses = nil
+}
+
+// AddStatuses прибавляет к сводке задачи счётчики статусов ссылок
+func (j *Job) AddStatuses(counts map[UrlStatus]int) {
+	if len(counts) == 0 {
+		return
+	}
+	if j.Statuses == nil {
+		j.Statuses = make(map[UrlStatus]int, len(counts))
+	}
+	for status, n := range counts {
+		j.Statuses[status] += n
+	}
+}
+
+// Copy возвращает копию задачи, безопасную для отдачи наружу
+func (j *Job) Copy() *Job {
+	c := *j
+	c.Errors = append([]string(nil), j.Errors...)
+	if j.Statuses != nil {
+		c.Statuses = make(map[UrlStatus]int, len(j.Statuses))
+		for status, n := range j.Statuses {
+			c.Statuses[status] = n
+		}
+	}
+	if j.RetryAt != nil {
+		t := *j.RetryAt
+		c.RetryAt = &t
+	}
+
+	return &c
 }
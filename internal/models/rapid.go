@@ -1,7 +1,6 @@
 package models
 
 import (
-	"fmt"
 	"time"
 
 	"inst_parser/internal/utils"
@@ -21,16 +20,18 @@ type InstagramReelInfo struct {
 	Virality    string
 	ParsingDate string
 	PublishDate string
+	ContentType string // reel, video, photo или carousel
 	Date        time.Time
 }
 
-// ProcessInstagramReelResponse - конвертирует API response в вашу модель
+// ProcessInstagramReelResponse - конвертирует публикацию из ленты аккаунта: reels, видео, фото или карусель
 func ProcessInstagramReelResponse(apiReel *InstagramMedia, accountURL string) *InstagramReelInfo {
 	// Получаем значения с проверкой на нулевые значения
 	likes := apiReel.LikeCount
 	comments := apiReel.CommentCount
 	shares := apiReel.ReshareCount
-	views := apiReel.IgPlayCount
+	views := apiReel.Views()
+	mediaType := apiReel.Type()
 
 	// Форматируем дату публикации
 	publishDate := ""
@@ -41,8 +42,7 @@ func ProcessInstagramReelResponse(apiReel *InstagramMedia, accountURL string) *I
 
 	// Создаем строку результата
 	return &InstagramReelInfo{
-		//todo create func
-		URL:         fmt.Sprintf("https://www.instagram.com/reel/%s/", apiReel.Code),
+		URL:         InstagramMediaURL(apiReel.Code, mediaType),
 		AccountURL:  accountURL,
 		Description: apiReel.Caption.Text,
 		Views:       views,
@@ -53,6 +53,8 @@ func ProcessInstagramReelResponse(apiReel *InstagramMedia, accountURL string) *I
 		Virality:    utils.GetVirality(int64(shares), int64(views)),
 		ParsingDate: utils.ParsingDate(),
 		PublishDate: publishDate,
+		ContentType: string(mediaType),
+		Date:        utils.UnixTime(apiReel.TakenAt),
 	}
 }
 
@@ -83,7 +85,7 @@ func InstagramReelInfoToInterface(data []*InstagramReelInfo) [][]interface{} {
 			data[i].PublishDate,
 			data[i].Description,
 		}
-		values = append(values, rowValues)
+		values = append(values, accountRow(rowValues, data[i].ContentType, ""))
 	}
 
 	return values
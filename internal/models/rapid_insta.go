@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////
 ////
 ////     MEDIA INFO
@@ -26,11 +28,17 @@ type RealTimeScraperMediaInfoData struct {
 	// ReshareCount может отсутствовать, используем указатель
 	ReshareCount *int64 `json:"reshare_count,omitempty"`
 	IgPlayCount  int64  `json:"ig_play_count,omitempty"` // Просмотры для видео
+	PlayCount    *int64 `json:"play_count,omitempty"`
 
 	// Информация о видео
 	VideoVersions []MediaInfoVideoVersion `json:"video_versions,omitempty"`
 	HasAudio      bool                    `json:"has_audio"`
 
+	// Фото и карусели
+	ImageVersions2     InstagramImageVersions   `json:"image_versions2"`
+	CarouselMediaCount int                      `json:"carousel_media_count,omitempty"`
+	CarouselMedia      []InstagramCarouselMedia `json:"carousel_media,omitempty"`
+
 	// Информация о пользователе
 	User struct {
 		Username   string `json:"username"`
@@ -102,11 +110,29 @@ type InstagramReelItem struct {
 	Media InstagramMedia `json:"media"`
 }
 
+// UnmarshalJSON user_reels оборачивает публикацию в media, user_posts отдаёт её как есть
+func (i *InstagramReelItem) UnmarshalJSON(data []byte) error {
+	var wrapped struct {
+		Media *InstagramMedia `json:"media"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+
+	if wrapped.Media != nil {
+		i.Media = *wrapped.Media
+		return nil
+	}
+
+	return json.Unmarshal(data, &i.Media)
+}
+
 type InstagramMedia struct {
 	TakenAt       int64            `json:"taken_at"`
 	PK            string           `json:"pk"`
 	ID            string           `json:"id"`
 	MediaType     int              `json:"media_type"`
+	ProductType   string           `json:"product_type"`
 	Code          string           `json:"code"`
 	Caption       InstagramCaption `json:"caption,omitempty"`
 	PlayCount     int              `json:"play_count"`
@@ -114,6 +140,7 @@ type InstagramMedia struct {
 	CommentCount  int              `json:"comment_count"`
 	ReshareCount  int              `json:"reshare_count"`
 	IgPlayCount   int              `json:"ig_play_count"`
+	ViewCount     int              `json:"view_count"`
 	VideoDuration float64          `json:"video_duration"`
 	HasAudio      bool             `json:"has_audio"`
 }
@@ -121,3 +148,51 @@ type InstagramMedia struct {
 type InstagramCaption struct {
 	Text string `json:"text"`
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////
+////
+////     USER INFO
+////
+/////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type RealTimeScraperUserInfoResponse struct {
+	Status  string                  `json:"status"`
+	Message string                  `json:"message,omitempty"`
+	Data    RealTimeScraperUserInfo `json:"data"`
+}
+
+// RealTimeScraperUserInfo профиль аккаунта
+type RealTimeScraperUserInfo struct {
+	Username            string `json:"username"`
+	FullName            string `json:"full_name"`
+	IsVerified          bool   `json:"is_verified"`
+	FollowerCount       int64  `json:"follower_count"`
+	FollowingCount      int64  `json:"following_count"`
+	MediaCount          int64  `json:"media_count"`
+	ProfilePicURL       string `json:"profile_pic_url"`
+	HDProfilePicURLInfo struct {
+		URL string `json:"url"`
+	} `json:"hd_profile_pic_url_info"`
+}
+
+func (u *RealTimeScraperUserInfo) ToProfile() *AccountProfile {
+	avatar := u.HDProfilePicURLInfo.URL
+	if avatar == "" {
+		avatar = u.ProfilePicURL
+	}
+
+	displayName := u.FullName
+	if displayName == "" {
+		displayName = u.Username
+	}
+
+	return &AccountProfile{
+		Platform:    InstagramParsingType,
+		DisplayName: displayName,
+		Followers:   u.FollowerCount,
+		Following:   u.FollowingCount,
+		MediaCount:  u.MediaCount,
+		Verified:    u.IsVerified,
+		AvatarURL:   avatar,
+	}
+}
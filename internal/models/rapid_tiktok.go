@@ -9,15 +9,38 @@ import (
 
 type TikTokUserSearchResponse struct {
 	Data struct {
-		UserList []struct {
-			User struct {
-				Id       string `json:"id"`
-				Nickname string `json:"nickname"`
-			} `json:"user"`
-		} `json:"user_list"`
+		UserList []TikTokSearchUser `json:"user_list"`
 	} `json:"data"`
 }
 
+// TikTokSearchUser аккаунт из поиска: профиль и счётчики
+type TikTokSearchUser struct {
+	User struct {
+		Id          string `json:"id"`
+		UniqueID    string `json:"uniqueId"`
+		Nickname    string `json:"nickname"`
+		AvatarThumb string `json:"avatarThumb"`
+		Verified    bool   `json:"verified"`
+	} `json:"user"`
+	Stats struct {
+		FollowerCount  int64 `json:"followerCount"`
+		FollowingCount int64 `json:"followingCount"`
+		VideoCount     int64 `json:"videoCount"`
+	} `json:"stats"`
+}
+
+func (u *TikTokSearchUser) ToProfile() *AccountProfile {
+	return &AccountProfile{
+		Platform:    TiktokParsingType,
+		DisplayName: u.User.Nickname,
+		Followers:   u.Stats.FollowerCount,
+		Following:   u.Stats.FollowingCount,
+		MediaCount:  u.Stats.VideoCount,
+		Verified:    u.User.Verified,
+		AvatarURL:   u.User.AvatarThumb,
+	}
+}
+
 type TikTokVideo struct {
 	Id           string `json:"id"`
 	VideoId      string `json:"video_id"`
@@ -76,15 +99,16 @@ func (t *TikTokVideo) ToResultRow(url string) (*ResultRowUrl, error) {
 	return result, nil
 }
 
-func TikTokVideoApiResponseToInterface(data []*TikTokVideo, accountUrl string) [][]interface{} {
+// TikTokVideoApiResponseToInterface строки AccountTable: ссылка на аккаунт, затем колонки видео
+func TikTokVideoApiResponseToInterface(data []*TikTokVideo, accountUrl, accountName string) [][]interface{} {
 	values := make([][]interface{}, 0, len(data))
 
 	for i := range data {
-		if data == nil {
+		if data[i] == nil {
 			continue
 		}
-		result, _ := data[i].ToResultRow(accountUrl)
-		values = append(values, ResultRowToInterface(result))
+		result, _ := data[i].ToResultRow(getTiktokUrl(accountName, data[i].VideoId))
+		values = append(values, append([]interface{}{accountUrl}, ResultRowToInterface(result)...))
 	}
 
 	return values
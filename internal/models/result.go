@@ -14,6 +14,57 @@ const defaultReelCount = 12
 type UrlInfo struct {
 	URL   string
 	Count int
+	Row   int // номер строки в листе, 1-based
+	// Canonical нормализованная ссылка на ролик, пустая — ссылка не распознана
+	Canonical *CanonicalURL
+	// Resolved куда ведёт короткая ссылка, пустая — ссылка не короткая или не раскрыта
+	Resolved string
+	// Window окно дат публикации для аккаунта, из колонки "Глубина" (30d) или из запроса
+	Window DateWindow
+}
+
+// NewUrlInfo ссылка из листа вместе с её каноническим видом
+func NewUrlInfo(url string, count, row int) *UrlInfo {
+	info := &UrlInfo{URL: url, Count: count, Row: row}
+	if canonical, ok := Canonicalize(url); ok {
+		info.Canonical = &canonical
+	}
+
+	return info
+}
+
+// FetchURL ссылка для запроса к провайдеру
+func (u *UrlInfo) FetchURL() string {
+	if u.Canonical != nil {
+		return u.Canonical.URL
+	}
+	if u.Resolved != "" {
+		return u.Resolved
+	}
+
+	return u.URL
+}
+
+// SetResolved запоминает, куда ведёт короткая ссылка, и приводит её к каноническому виду
+func (u *UrlInfo) SetResolved(resolved string) {
+	u.Resolved = resolved
+	if canonical, ok := Canonicalize(resolved); ok {
+		u.Canonical = &canonical
+	}
+}
+
+// NeedsResolve true для короткой ссылки, которую не удалось разобрать без перехода по ней
+func (u *UrlInfo) NeedsResolve() bool {
+	return u.Canonical == nil && u.Resolved == "" && IsShortLink(u.URL)
+}
+
+// ContentKey строки с одинаковым ключом ссылаются на один ролик
+func (u *UrlInfo) ContentKey() string {
+	if u.Canonical != nil {
+		return u.Canonical.Key()
+	}
+
+	return u.FetchURL()
 }
 
 func DefaultUrlInfo(url string) *UrlInfo {
@@ -52,7 +103,7 @@ func ProcessInstagramResponse(
 ) (*ResultRowUrl, error) {
 	//Проверяем наличие items
 	if len(apiResponse.Data.Items) == 0 {
-		return nil, fmt.Errorf("no items found in API response")
+		return nil, fmt.Errorf("%w: no items found in API response", ErrContentNotFound)
 	}
 
 	item := apiResponse.Data.Items[0]
@@ -61,7 +112,7 @@ func ProcessInstagramResponse(
 	likes := item.LikeCount
 	comments := item.CommentCount
 	shares := item.ReshareCount
-	views := item.IgPlayCount
+	views := item.Views()
 
 	// Форматируем дату публикации
 	var publishDate string
@@ -78,8 +129,10 @@ func ProcessInstagramResponse(
 	}
 
 	videoUrls := make([]string, len(item.VideoVersions))
+	var mediaUrls []string
 	if needFindVideoUrl {
 		videoUrls = findVideoUrls(apiResponse)
+		mediaUrls = item.MediaURLs()
 	}
 
 	// Создаем строку результата
@@ -96,6 +149,8 @@ func ProcessInstagramResponse(
 		ParsingDate: utils.ParsingDate(),
 		PublishDate: publishDate,
 		VideoUrls:   videoUrls,
+		MediaUrls:   mediaUrls,
+		ContentType: string(item.Type()),
 	}
 
 	return result, nil
@@ -129,7 +184,7 @@ func ResultRowsToInterface(results []*ResultRowUrl) [][]interface{} {
 			results[i].Views,
 			results[i].Likes,
 			results[i].Comments,
-			results[i].Shares,
+			results[i].SharesCell(),
 			results[i].ER,
 			results[i].Virality,
 			results[i].ParsingDate,
@@ -139,6 +194,13 @@ func ResultRowsToInterface(results []*ResultRowUrl) [][]interface{} {
 			results[i].ErID,
 			results[i].INN,
 			results[i].AdvertiserName,
+			string(results[i].Status),
+			results[i].Reason,
+			results[i].CanonicalURL,
+			results[i].ContentType,
+			results[i].Duration,
+			results[i].ViewsGain24h,
+			results[i].ViewsGain7d,
 		}
 		values = append(values, rowValues)
 	}
@@ -146,13 +208,26 @@ func ResultRowsToInterface(results []*ResultRowUrl) [][]interface{} {
 	return values
 }
 
+// ResultRowsFailed признаки ошибки строк в порядке ResultRowsToInterface
+func ResultRowsFailed(results []*ResultRowUrl) []bool {
+	failed := make([]bool, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		failed = append(failed, result.Failed())
+	}
+
+	return failed
+}
+
 func ResultRowToInterface(result *ResultRowUrl) []interface{} {
 	return []interface{}{
 		result.URL,
 		result.Views,
 		result.Likes,
 		result.Comments,
-		result.Shares,
+		result.SharesCell(),
 		result.ER,
 		result.Virality,
 		result.ParsingDate,
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"inst_parser/internal/constants"
+	"inst_parser/internal/utils"
+)
+
+// RutubeVideo ролик из публичного API rutube.ru/api/video/
+type RutubeVideo struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Duration      int    `json:"duration"` // секунды
+	Hits          int64  `json:"hits"`     // просмотры
+	CommentsCount int64  `json:"comments_count"`
+	CreatedTs     string `json:"created_ts"`
+	PublicationTs string `json:"publication_ts"`
+	IsLivestream  bool   `json:"is_livestream"`
+	Author        struct {
+		ID      int64  `json:"id"`
+		Name    string `json:"name"`
+		SiteURL string `json:"site_url"`
+	} `json:"author"`
+}
+
+// RutubeVideosPage страница роликов канала rutube.ru/api/video/person/
+type RutubeVideosPage struct {
+	HasNext bool           `json:"has_next"`
+	Page    int            `json:"page"`
+	Results []*RutubeVideo `json:"results"`
+}
+
+// URL ссылка на ролик
+func (v *RutubeVideo) URL() string {
+	return fmt.Sprintf("https://rutube.ru/video/%s/", v.ID)
+}
+
+// PublishedAt время публикации; у неопубликованных роликов — время загрузки
+func (v *RutubeVideo) PublishedAt() time.Time {
+	publishDate := v.PublicationTs
+	if publishDate == "" {
+		publishDate = v.CreatedTs
+	}
+
+	// время в API без зоны, считаем его московским
+	t, _ := time.ParseInLocation(constants.RutubeDateFormat, publishDate, utils.Moscow())
+	return t
+}
+
+func (v *RutubeVideo) ToResultRow(url string) *ResultRowUrl {
+	publishDate := v.PublicationTs
+	if publishDate == "" {
+		publishDate = v.CreatedTs
+	}
+
+	ownerUrl := v.Author.SiteURL
+	if ownerUrl == "" && v.Author.ID > 0 {
+		ownerUrl = fmt.Sprintf("https://rutube.ru/channel/%d/", v.Author.ID)
+	}
+
+	var duration string
+	if v.Duration > 0 {
+		duration = utils.FormatDuration(time.Duration(v.Duration) * time.Second)
+	}
+
+	// лайков и репостов публичное API не отдаёт
+	return &ResultRowUrl{
+		OwnerUrl:    ownerUrl,
+		URL:         url,
+		Description: v.Title,
+		Views:       v.Hits,
+		Comments:    v.CommentsCount,
+		ER:          utils.GetER(0, 0, v.CommentsCount, v.Hits),
+		Virality:    utils.GetVirality(0, v.Hits),
+		ParsingDate: utils.ParsingDate(),
+		PublishDate: utils.FormatParsingDate(publishDate),
+		Duration:    duration,
+	}
+}
+
+// RutubeVideosToInterface строки AccountTable: ссылка на канал, затем колонки видео
+func RutubeVideosToInterface(data []*RutubeVideo, accountUrl string) [][]interface{} {
+	values := make([][]interface{}, 0, len(data))
+
+	for i := range data {
+		if data[i] == nil {
+			continue
+		}
+		row := ResultRowToInterface(data[i].ToResultRow(data[i].URL()))
+		values = append(values, append([]interface{}{accountUrl}, row...))
+	}
+
+	return values
+}
+
+func ClipMoneyResultRowFromRutubeVideos(data []*RutubeVideo, accountUrl string) []*ClipMoneyResultRow {
+	result := make([]*ClipMoneyResultRow, 0, len(data))
+
+	for i := range data {
+		if data[i] == nil {
+			continue
+		}
+		row := data[i].ToResultRow(data[i].URL())
+
+		result = append(result, &ClipMoneyResultRow{
+			AccountUrl:  accountUrl,
+			URL:         row.URL,
+			Description: row.Description,
+			Views:       row.Views,
+			Comments:    row.Comments,
+			ER:          row.ER,
+			Virality:    row.Virality,
+			ParsingDate: row.ParsingDate,
+			PublishDate: row.PublishDate,
+		})
+	}
+
+	return result
+}
+
+// rutubeVideoPathRe /video/ID/, /shorts/ID/, /play/embed/ID; ID — 32 hex-символа
+var rutubeVideoPathRe = regexp.MustCompile(`^/(?:video|shorts|play/embed)/([0-9a-f]{32})(?:/|$)`)
+
+// ExtractRutubeVideoID ID ролика из ссылки rutube.ru
+func ExtractRutubeVideoID(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || !isRutubeHost(u.Hostname()) {
+		return "", false
+	}
+
+	m := rutubeVideoPathRe.FindStringSubmatch(strings.ToLower(u.Path))
+	if m == nil {
+		return "", false
+	}
+
+	return m[1], true
+}
+
+func isRutubeHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return host == "rutube.ru"
+}
@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestExtractRutubeVideoID(t *testing.T) {
+	type args struct {
+		url string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  string
+		want1 bool
+	}{
+		{name: "video", args: args{url: "https://rutube.ru/video/0123456789abcdef0123456789abcdef/"}, want: "0123456789abcdef0123456789abcdef", want1: true},
+		{name: "video with query", args: args{url: "rutube.ru/video/0123456789abcdef0123456789abcdef/?r=wd"}, want: "0123456789abcdef0123456789abcdef", want1: true},
+		{name: "shorts", args: args{url: "https://rutube.ru/shorts/0123456789abcdef0123456789abcdef"}, want: "0123456789abcdef0123456789abcdef", want1: true},
+		{name: "embed", args: args{url: "https://rutube.ru/play/embed/0123456789abcdef0123456789abcdef"}, want: "0123456789abcdef0123456789abcdef", want1: true},
+		{name: "channel", args: args{url: "https://rutube.ru/channel/42/"}, want: "", want1: false},
+		{name: "not rutube", args: args{url: "https://vk.com/video/0123456789abcdef0123456789abcdef"}, want: "", want1: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := ExtractRutubeVideoID(tt.args.url)
+			if got != tt.want {
+				t.Errorf("ExtractRutubeVideoID() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("ExtractRutubeVideoID() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestRutubeURLs(t *testing.T) {
+	if got := ParsingTypeByUrl("https://rutube.ru/video/0123456789abcdef0123456789abcdef/"); got != RutubeParsingType {
+		t.Errorf("ParsingTypeByUrl() = %v, want %v", got, RutubeParsingType)
+	}
+
+	account, parsingType, err := ParseSocialAccountURL("https://rutube.ru/channel/23178409/videos/")
+	if err != nil || account != "23178409" || parsingType != RutubeParsingType {
+		t.Errorf("ParseSocialAccountURL() = %v, %v, %v", account, parsingType, err)
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+)
+
+// Schedule регулярный запуск парсинга листа таблицы
+type Schedule struct {
+	ID            string     `json:"id"`
+	SpreadsheetID string     `json:"spreadsheet_id"`
+	SheetName     string     `json:"sheet_name"`
+	IsSelected    bool       `json:"is_selected"`
+	WriteBack     bool       `json:"write_back,omitempty"` // только для parse_urls
+	FullFeed      bool       `json:"full_feed,omitempty"`  // только для parse_account
+	Window        DateWindow `json:"window,omitzero"`      // только для parse_account: окно дат публикации
+	Upsert        bool       `json:"upsert,omitempty"`     // обновлять строки записанных роликов по ссылке
+	Kind          string     `json:"kind"`                 // parse_urls или parse_account
+	Spec          string     `json:"spec"`                 // "every 6 hours", "daily 09:00 Europe/Moscow" или cron
+	CreatedAt     time.Time  `json:"created_at"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastJobID     string     `json:"last_job_id,omitempty"`
+	LastError     string     `json:"last_error,omitempty"` // ошибка постановки в очередь при последнем запуске
+	NextRunAt     time.Time  `json:"next_run_at"`
+}
+
+// Payload параметры задачи для очередного запуска расписания
+func (s *Schedule) Payload() SheetPayload {
+	return SheetPayload{
+		SpreadsheetID: s.SpreadsheetID,
+		SheetName:     s.SheetName,
+		IsSelected:    s.IsSelected,
+		WriteBack:     s.WriteBack,
+		FullFeed:      s.FullFeed,
+		Window:        s.Window,
+		Upsert:        s.Upsert,
+	}
+}
+
+// Copy возвращает копию расписания, безопасную для отдачи наружу
+func (s *Schedule) Copy() *Schedule {
+	c := *s
+	if s.LastRunAt != nil {
+		t := *s.LastRunAt
+		c.LastRunAt = &t
+	}
+
+	return &c
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// shortLinkHosts сервисы коротких ссылок и платформа, на которую они ведут
+var shortLinkHosts = map[string]ParsingType{
+	"vt.tiktok.com": TiktokParsingType,
+	"vm.tiktok.com": TiktokParsingType,
+	"vk.cc":         VKGroupParsingType,
+	"instagr.am":    InstagramParsingType,
+	"youtu.be":      YoutubeParsingType,
+}
+
+// ShortLinkPlatform платформа короткой ссылки. false — ссылка не короткая.
+func ShortLinkPlatform(raw string) (ParsingType, bool) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if platform, ok := shortLinkHosts[host]; ok {
+		return platform, true
+	}
+
+	// tiktok.com/t/ZTxxxx — короткая ссылка из приложения
+	if (host == "tiktok.com" || host == "m.tiktok.com") && strings.HasPrefix(u.Path, "/t/") {
+		return TiktokParsingType, true
+	}
+
+	return "", false
+}
+
+// IsShortLink true для ссылок сервисов сокращения, которые нужно раскрыть перед парсингом
+func IsShortLink(raw string) bool {
+	_, ok := ShortLinkPlatform(raw)
+	return ok
+}
@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestShortLinkPlatform(t *testing.T) {
+	type args struct {
+		raw string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   ParsingType
+		wantOk bool
+	}{
+		{name: "vt.tiktok.com", args: args{raw: "https://vt.tiktok.com/ZSabc123/"}, want: TiktokParsingType, wantOk: true},
+		{name: "tiktok app link", args: args{raw: "https://www.tiktok.com/t/ZTabc123/"}, want: TiktokParsingType, wantOk: true},
+		{name: "vk.cc", args: args{raw: "vk.cc/cAbC12"}, want: VKGroupParsingType, wantOk: true},
+		{name: "instagr.am", args: args{raw: "http://instagr.am/p/ABC/"}, want: InstagramParsingType, wantOk: true},
+		{name: "full tiktok link", args: args{raw: "https://www.tiktok.com/@user/video/123"}, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ShortLinkPlatform(tt.args.raw)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ShortLinkPlatform() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestUrlInfo_SetResolved(t *testing.T) {
+	info := NewUrlInfo("https://vt.tiktok.com/ZSabc123/", 12, 3)
+	if !info.NeedsResolve() {
+		t.Fatalf("NeedsResolve() = false for short link")
+	}
+
+	info.SetResolved("https://www.tiktok.com/@user/video/7412345678901234567?_r=1")
+
+	if info.NeedsResolve() {
+		t.Errorf("NeedsResolve() = true after SetResolved()")
+	}
+	if got := info.ContentKey(); got != "tiktok:7412345678901234567" {
+		t.Errorf("ContentKey() = %v, want tiktok:7412345678901234567", got)
+	}
+	if got := info.FetchURL(); got != "https://www.tiktok.com/@user/video/7412345678901234567" {
+		t.Errorf("FetchURL() = %v, want canonical tiktok url", got)
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"inst_parser/internal/utils"
+)
+
+// TelegramPost пост публичного канала из веб-превью t.me/s/.
+// Пересылок в веб-превью нет (их отдаёт только MTProto-клиент с аккаунтом), поэтому
+// колонка репостов у постов пустая, ER считается без них, виральность не считается.
+type TelegramPost struct {
+	Channel     string
+	ID          int
+	Text        string
+	Views       int64
+	Reactions   int64 // сумма всех реакций, идёт в колонку лайков
+	PublishedAt time.Time
+}
+
+// URL ссылка на пост
+func (p *TelegramPost) URL() string {
+	return fmt.Sprintf("https://t.me/%s/%d", p.Channel, p.ID)
+}
+
+func (p *TelegramPost) ToResultRow(url string) *ResultRowUrl {
+	var publishDate string
+	if !p.PublishedAt.IsZero() {
+		publishDate = utils.PublishDate(p.PublishedAt)
+	}
+
+	return &ResultRowUrl{
+		OwnerUrl:    fmt.Sprintf("https://t.me/%s", p.Channel),
+		URL:         url,
+		Description: p.Text,
+		Views:       p.Views,
+		Likes:       p.Reactions,
+		NoShares:    true,
+		ER:          utils.GetER(p.Reactions, 0, 0, p.Views),
+		ParsingDate: utils.ParsingDate(),
+		PublishDate: publishDate,
+	}
+}
+
+// TelegramPostsToInterface строки AccountTable: ссылка на канал, затем колонки поста
+func TelegramPostsToInterface(data []*TelegramPost, accountUrl string) [][]interface{} {
+	values := make([][]interface{}, 0, len(data))
+
+	for i := range data {
+		if data[i] == nil {
+			continue
+		}
+		row := ResultRowToInterface(data[i].ToResultRow(data[i].URL()))
+		values = append(values, append([]interface{}{accountUrl}, row...))
+	}
+
+	return values
+}
+
+func ClipMoneyResultRowFromTelegramPosts(data []*TelegramPost, accountUrl string) []*ClipMoneyResultRow {
+	result := make([]*ClipMoneyResultRow, 0, len(data))
+
+	for i := range data {
+		if data[i] == nil {
+			continue
+		}
+		row := data[i].ToResultRow(data[i].URL())
+
+		result = append(result, &ClipMoneyResultRow{
+			AccountUrl:  accountUrl,
+			URL:         row.URL,
+			Description: row.Description,
+			Views:       row.Views,
+			Likes:       row.Likes,
+			Shares:      row.Shares,
+			ER:          row.ER,
+			Virality:    row.Virality,
+			ParsingDate: row.ParsingDate,
+			PublishDate: row.PublishDate,
+		})
+	}
+
+	return result
+}
+
+// ParseTelegramPostURL канал и номер поста из t.me/channel/123 или t.me/s/channel/123.
+// Ссылки на приватные чаты t.me/c/… не поддерживаются: у них нет веб-превью.
+func ParseTelegramPostURL(raw string) (string, int, bool) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || !isTelegramHost(u.Hostname()) {
+		return "", 0, false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 3 && parts[0] == "s" {
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "c" || parts[0] == "s" {
+		return "", 0, false
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return "", 0, false
+	}
+
+	return parts[0], id, true
+}
+
+func isTelegramHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	return host == "t.me" || host == "telegram.me"
+}
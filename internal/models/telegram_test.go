@@ -0,0 +1,70 @@
+package models
+
+import "testing"
+
+func TestParseTelegramPostURL(t *testing.T) {
+	type args struct {
+		url string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantChannel string
+		wantID      int
+		wantOk      bool
+	}{
+		{name: "post", args: args{url: "https://t.me/durov/123"}, wantChannel: "durov", wantID: 123, wantOk: true},
+		{name: "post with query", args: args{url: "https://t.me/durov/123?single"}, wantChannel: "durov", wantID: 123, wantOk: true},
+		{name: "preview post", args: args{url: "https://t.me/s/durov/123"}, wantChannel: "durov", wantID: 123, wantOk: true},
+		{name: "telegram.me without scheme", args: args{url: "telegram.me/durov/7"}, wantChannel: "durov", wantID: 7, wantOk: true},
+		{name: "channel", args: args{url: "https://t.me/durov"}, wantOk: false},
+		{name: "preview channel", args: args{url: "https://t.me/s/durov"}, wantOk: false},
+		{name: "private chat", args: args{url: "https://t.me/c/1234567/89"}, wantOk: false},
+		{name: "not telegram", args: args{url: "https://vk.com/durov/123"}, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, id, ok := ParseTelegramPostURL(tt.args.url)
+			if ok != tt.wantOk {
+				t.Fatalf("ParseTelegramPostURL() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if channel != tt.wantChannel || id != tt.wantID {
+				t.Errorf("ParseTelegramPostURL() = %v, %v, want %v, %v", channel, id, tt.wantChannel, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTelegramURLs(t *testing.T) {
+	const url = "https://t.me/s/Durov/123"
+
+	if got := ParsingTypeByUrl(url); got != TelegramParsingType {
+		t.Errorf("ParsingTypeByUrl() = %v, want %v", got, TelegramParsingType)
+	}
+	if !IsAvailableByParsingType(url, []ParsingType{TelegramParsingType}) {
+		t.Errorf("IsAvailableByParsingType() = false, want true")
+	}
+
+	canonical, ok := Canonicalize(url)
+	if !ok || canonical.Key() != "telegram:durov/123" || canonical.URL != "https://t.me/Durov/123" {
+		t.Errorf("Canonicalize() = %+v, %v", canonical, ok)
+	}
+
+	account, parsingType, err := ParseSocialAccountURL("https://t.me/s/durov")
+	if err != nil || account != "durov" || parsingType != TelegramParsingType {
+		t.Errorf("ParseSocialAccountURL() = %v, %v, %v", account, parsingType, err)
+	}
+}
+
+func TestTelegramPost_ToResultRow(t *testing.T) {
+	post := &TelegramPost{Channel: "channel", ID: 1, Views: 200, Reactions: 10}
+
+	row := ResultRowToInterface(post.ToResultRow(post.URL()))
+	// пересылок нет: репосты и виральность пустые, ER только по реакциям
+	if row[4] != "" || row[6] != "" {
+		t.Errorf("shares, virality = %v, %v, want empty", row[4], row[6])
+	}
+	if row[5] != "5.00%" {
+		t.Errorf("ER = %v, want 5.00%%", row[5])
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"inst_parser/internal/utils"
+)
+
+// RowUpdate новые значения существующей строки листа
+type RowUpdate struct {
+	Row    int // 1-based номер строки
+	Values []interface{}
+}
+
+// Range A1-диапазон строки в листе от firstColumn шириной в её значения: строки
+// результатов разной ширины, а запись строки шире диапазона Sheets отклоняет
+func (u *RowUpdate) Range(sheetName, firstColumn string) string {
+	lastColumn := utils.ColumnLetter(utils.ColumnNumber(firstColumn) + max(len(u.Values), 1) - 1)
+
+	return fmt.Sprintf("%s!%s%d:%s%d", sheetName, firstColumn, u.Row, lastColumn, u.Row)
+}
+
+// UpsertKey ключ строки результата по ссылке на ролик: разные формы ссылки
+// на один ролик дают один ключ, нераспознанная ссылка — ключ сама по себе
+func UpsertKey(url string) string {
+	if canonical, ok := Canonicalize(url); ok {
+		return canonical.Key()
+	}
+
+	return strings.TrimSpace(url)
+}
+
+// PlanUpsert раскладывает строки результата на обновления и добавления.
+// existing — значения колонки ссылок листа сверху вниз, keyColumn — 0-based индекс
+// ячейки со ссылкой на ролик в строке результата.
+// Ролик, уже записанный в лист, обновляет первую найденную строку; повтор ролика
+// среди новых строк заменяет предыдущий, поэтому побеждают последние данные.
+// failed — строки с ошибкой парсинга (nil — таких нет): в них нули вместо метрик,
+// поэтому они добавляются только для новых роликов и не затирают уже собранные данные.
+func PlanUpsert(existing []string, keyColumn int, rows [][]interface{}, failed []bool) ([]*RowUpdate, [][]interface{}) {
+	rowByKey := make(map[string]int, len(existing))
+	for i, value := range existing {
+		key := UpsertKey(value)
+		if key == "" {
+			continue
+		}
+		if _, ok := rowByKey[key]; !ok {
+			rowByKey[key] = i + 1
+		}
+	}
+
+	var (
+		updates     []*RowUpdate
+		appends     [][]interface{}
+		updateByRow = make(map[int]*RowUpdate)
+		appendByKey = make(map[string]int)
+		// appendFailed добавления из строк с ошибкой, их заменяет следующая удачная строка ролика
+		appendFailed = make(map[int]bool)
+	)
+	for i, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		rowFailed := i < len(failed) && failed[i]
+
+		var key string
+		if keyColumn < len(row) {
+			key = UpsertKey(fmt.Sprint(row[keyColumn]))
+		}
+		if key == "" {
+			appends = append(appends, row)
+			continue
+		}
+
+		if n, ok := rowByKey[key]; ok {
+			if rowFailed {
+				continue
+			}
+			if update, ok := updateByRow[n]; ok {
+				update.Values = row
+				continue
+			}
+			update := &RowUpdate{Row: n, Values: row}
+			updateByRow[n] = update
+			updates = append(updates, update)
+			continue
+		}
+
+		if j, ok := appendByKey[key]; ok {
+			if rowFailed && !appendFailed[j] {
+				continue
+			}
+			appends[j] = row
+			appendFailed[j] = rowFailed
+			continue
+		}
+		appendByKey[key] = len(appends)
+		appendFailed[len(appends)] = rowFailed
+		appends = append(appends, row)
+	}
+
+	return updates, appends
+}
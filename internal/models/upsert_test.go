@@ -0,0 +1,282 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"inst_parser/internal/constants"
+)
+
+func TestPlanUpsert(t *testing.T) {
+	// повторный парсинг: записанный ролик упал по лимиту, новый ролик тоже с ошибкой
+	failedResults := []*ResultRowUrl{
+		FailedResultRow("https://vk.com/clip-1_1", ErrRateLimited),
+		FailedResultRow("https://vk.com/clip-1_3", ErrContentNotFound),
+	}
+	failedReparse := struct {
+		rows   [][]interface{}
+		failed []bool
+	}{ResultRowsToInterface(failedResults), ResultRowsFailed(failedResults)}
+
+	type args struct {
+		existing  []string
+		keyColumn int
+		rows      [][]interface{}
+		failed    []bool
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantUpdates []*RowUpdate
+		wantAppends [][]interface{}
+	}{
+		{
+			name: "updates known video by canonical url",
+			args: args{
+				existing: []string{"Ссылка", "https://www.instagram.com/reel/ABC/", "https://vk.com/clip-1_2"},
+				rows: [][]interface{}{
+					{"https://instagram.com/p/ABC/?igsh=x", 100},
+					{"https://vk.ru/clip-1_2", 50},
+				},
+			},
+			wantUpdates: []*RowUpdate{
+				{Row: 2, Values: []interface{}{"https://instagram.com/p/ABC/?igsh=x", 100}},
+				{Row: 3, Values: []interface{}{"https://vk.ru/clip-1_2", 50}},
+			},
+		},
+		{
+			name: "appends new video",
+			args: args{
+				existing: []string{"Ссылка", "https://www.instagram.com/reel/ABC/"},
+				rows:     [][]interface{}{{"https://www.instagram.com/reel/NEW/", 1}},
+			},
+			wantAppends: [][]interface{}{{"https://www.instagram.com/reel/NEW/", 1}},
+		},
+		{
+			name: "last row of repeated video wins",
+			args: args{
+				existing: []string{"Ссылка", "https://t.me/channel/1"},
+				rows: [][]interface{}{
+					{"https://t.me/channel/1", 1},
+					{"https://t.me/channel/2", 1},
+					{"https://t.me/s/channel/1", 2},
+					{"https://t.me/channel/2", 3},
+				},
+			},
+			wantUpdates: []*RowUpdate{{Row: 2, Values: []interface{}{"https://t.me/s/channel/1", 2}}},
+			wantAppends: [][]interface{}{{"https://t.me/channel/2", 3}},
+		},
+		{
+			name: "account rows keyed on video column",
+			args: args{
+				existing:  []string{"Ссылка на ролик", "https://vk.com/clip-1_2"},
+				keyColumn: 1,
+				rows: [][]interface{}{
+					{"https://vk.com/club1", "https://vk.com/clip-1_1", 1},
+					{"https://vk.com/club1", "https://vk.com/clip-1_2", 2},
+					{"https://vk.com/club1", "https://vk.com/clip-1_3", 3},
+				},
+			},
+			wantUpdates: []*RowUpdate{{Row: 2, Values: []interface{}{"https://vk.com/club1", "https://vk.com/clip-1_2", 2}}},
+			wantAppends: [][]interface{}{
+				{"https://vk.com/club1", "https://vk.com/clip-1_1", 1},
+				{"https://vk.com/club1", "https://vk.com/clip-1_3", 3},
+			},
+		},
+		{
+			name: "row without key column is appended",
+			args: args{
+				existing:  []string{"Ссылка", "https://vk.com/clip-1_1"},
+				keyColumn: 1,
+				rows:      [][]interface{}{{"https://vk.com/clip-1_1"}},
+			},
+			wantAppends: [][]interface{}{{"https://vk.com/clip-1_1"}},
+		},
+		{
+			name: "failed re-parse keeps written video",
+			args: args{
+				existing:  []string{"Ссылка", "https://vk.com/clip-1_1"},
+				keyColumn: constants.DataTableKeyColumn,
+				rows:      failedReparse.rows,
+				failed:    failedReparse.failed,
+			},
+			wantAppends: failedReparse.rows[1:2],
+		},
+		{
+			name: "successful row of a video replaces its failed row in the batch",
+			args: args{
+				existing: []string{"Ссылка"},
+				rows: [][]interface{}{
+					{"https://vk.com/clip-1_2", 0},
+					{"https://vk.com/clip-1_2", 5},
+					{"https://vk.com/clip-1_2", 0},
+				},
+				failed: []bool{true, false, true},
+			},
+			wantAppends: [][]interface{}{{"https://vk.com/clip-1_2", 5}},
+		},
+		{
+			name: "first of duplicated sheet rows is updated",
+			args: args{
+				existing: []string{"", "https://vk.com/clip-1_2", "https://vk.com/clip-1_2"},
+				rows:     [][]interface{}{{"https://vk.com/clip-1_2", 7}},
+			},
+			wantUpdates: []*RowUpdate{{Row: 2, Values: []interface{}{"https://vk.com/clip-1_2", 7}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, appends := PlanUpsert(tt.args.existing, tt.args.keyColumn, tt.args.rows, tt.args.failed)
+			if !reflect.DeepEqual(updates, tt.wantUpdates) {
+				t.Errorf("PlanUpsert() updates = %v, want %v", updates, tt.wantUpdates)
+			}
+			if !reflect.DeepEqual(appends, tt.wantAppends) {
+				t.Errorf("PlanUpsert() appends = %v, want %v", appends, tt.wantAppends)
+			}
+		})
+	}
+}
+
+func TestRowUpdate_Range(t *testing.T) {
+	result := &ResultRowUrl{URL: "https://vk.com/clip-1_2", Status: UrlStatusOK}
+	reel := &InstagramReelInfo{AccountURL: "https://www.instagram.com/acc/", URL: "https://www.instagram.com/reel/ABC/"}
+
+	type args struct {
+		sheetName   string
+		firstColumn string
+		values      []interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "url result row",
+			args: args{sheetName: "Data", firstColumn: "A", values: ResultRowsToInterface([]*ResultRowUrl{result})[0]},
+			want: "Data!A5:U5",
+		},
+		{
+			name: "instagram account row",
+			args: args{sheetName: "Accounts", firstColumn: "A", values: InstagramReelInfoToInterface([]*InstagramReelInfo{reel})[0]},
+			want: "Accounts!A5:P5",
+		},
+		{
+			name: "range not from first column",
+			args: args{sheetName: "Data", firstColumn: "C", values: []interface{}{1, 2, 3}},
+			want: "Data!C5:E5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := &RowUpdate{Row: 5, Values: tt.args.values}
+			if got := update.Range(tt.args.sheetName, tt.args.firstColumn); got != tt.want {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanUpsert_sheetRows(t *testing.T) {
+	const accountURL = "https://example.com/account"
+
+	type args struct {
+		existing  []string
+		keyColumn int
+		rows      [][]interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "url results",
+			args: args{
+				existing:  []string{"Ссылка", "https://vk.ru/clip-1_1"},
+				keyColumn: constants.DataTableKeyColumn,
+				rows: ResultRowsToInterface([]*ResultRowUrl{
+					{URL: "https://vk.com/clip-1_1"},
+					{URL: "https://vk.com/clip-1_2"},
+				}),
+			},
+		},
+		{
+			name: "vk clips",
+			args: args{
+				existing:  []string{"Ссылка", "https://vk.ru/clip-1_1"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: VKClipsInfoToInterface([]*VKClipInfo{
+					{GroupUrl: accountURL, URL: "https://vk.com/clip-1_1"},
+					{GroupUrl: accountURL, URL: "https://vk.com/clip-1_2"},
+				}),
+			},
+		},
+		{
+			name: "instagram reels",
+			args: args{
+				existing:  []string{"Ссылка", "https://instagram.com/p/A1/"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: InstagramReelInfoToInterface([]*InstagramReelInfo{
+					{AccountURL: accountURL, URL: "https://www.instagram.com/reel/A1/"},
+					{AccountURL: accountURL, URL: "https://www.instagram.com/reel/A2/"},
+				}),
+			},
+		},
+		{
+			name: "youtube shorts",
+			args: args{
+				existing:  []string{"Ссылка", "https://youtu.be/aaaaaaaaaa1"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: YoutubeShortInfoApiResponseToInterface([]*YoutubeShortInfoApiResponse{
+					{ID: "aaaaaaaaaa1", ViewCount: "1", CommentCount: "0", ContentType: YoutubeContentShort},
+					{ID: "aaaaaaaaaa2", ViewCount: "1", CommentCount: "0", ContentType: YoutubeContentShort},
+				}, accountURL),
+			},
+		},
+		{
+			name: "tiktok videos",
+			args: args{
+				existing:  []string{"Ссылка", "https://www.tiktok.com/@acc/video/1"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: TikTokVideoApiResponseToInterface([]*TikTokVideo{
+					{VideoId: "1", PlayCount: 10},
+					{VideoId: "2", PlayCount: 10},
+				}, accountURL, "acc"),
+			},
+		},
+		{
+			name: "telegram posts",
+			args: args{
+				existing:  []string{"Ссылка", "https://t.me/s/channel/1"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: TelegramPostsToInterface([]*TelegramPost{
+					{Channel: "channel", ID: 1},
+					{Channel: "channel", ID: 2},
+				}, accountURL),
+			},
+		},
+		{
+			name: "rutube videos",
+			args: args{
+				existing:  []string{"Ссылка", "https://rutube.ru/video/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1/"},
+				keyColumn: constants.AccountTableKeyColumn,
+				rows: RutubeVideosToInterface([]*RutubeVideo{
+					{ID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1"},
+					{ID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa2"},
+				}, accountURL),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// записанный ролик обновляется, второй ролик того же аккаунта добавляется
+			updates, appends := PlanUpsert(tt.args.existing, tt.args.keyColumn, tt.args.rows, nil)
+			if len(updates) != 1 || updates[0].Row != 2 || !reflect.DeepEqual(updates[0].Values, tt.args.rows[0]) {
+				t.Errorf("PlanUpsert() updates = %v, want row 2 with %v", updates, tt.args.rows[0])
+			}
+			if !reflect.DeepEqual(appends, tt.args.rows[1:]) {
+				t.Errorf("PlanUpsert() appends = %v, want %v", appends, tt.args.rows[1:])
+			}
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 
 const (
 	instagramPattern = `(?:https?://)?(?:www\.)?instagram\.com/([^/?#]+)`
-	vkPattern        = `(?:https?://)?(?:www\.)?vk\.(?:com|ru)/(?:(?:club|id)(\d+)|([^/?#]+))`
-	telegramPattern  = `(?:https?://)?(?:www\.)?t\.me/([^/?#]+)`
-	youtubePattern   = `(?:https?://)?(?:www\.)?youtube\.com/(?:c/|channel/)?(@[^/?#]+)`
-	tiktokPattern    = `(?:https?://)?(?:www\.)?tiktok\.com/@([^/?#]+)`
+	vkPattern        = `(?:https?://)?(?:www\.)?vk(?:video)?\.(?:com|ru)/(?:(?:club|id)(\d+)|([^/?#]+))`
+	telegramPattern  = `(?:https?://)?(?:www\.)?(?:t|telegram)\.me/(?:s/)?([^/?#]+)`
+	// youtubePattern: /c/name и /user/name, /channel/UC… или @handle
+	youtubePattern = `(?:https?://)?(?:www\.|m\.)?youtube\.com/(?:(?:c|user)/([^/?#]+)|channel/(UC[\w-]{22})|(@[^/?#]+))`
+	tiktokPattern  = `(?:https?://)?(?:www\.)?tiktok\.com/@([^/?#]+)`
+	rutubePattern  = `(?:https?://)?(?:www\.|m\.)?rutube\.ru/channel/(\d+)`
 )
 
 func ParseSocialAccountURL(url string) (
@@ -26,6 +28,7 @@ func ParseSocialAccountURL(url string) (
 		TelegramParsingType:  regexp.MustCompile(telegramPattern),
 		YoutubeParsingType:   regexp.MustCompile(youtubePattern),
 		TiktokParsingType:    regexp.MustCompile(tiktokPattern),
+		RutubeParsingType:    regexp.MustCompile(rutubePattern),
 	}
 
 	for platformName, re := range patterns {
@@ -57,8 +60,11 @@ func ParseSocialAccountURL(url string) (
 			continue
 		}
 
-		if len(matches) > 1 && matches[1] != "" {
-			return matches[1], platformName, nil
+		// у YouTube несколько альтернативных групп, берём первую совпавшую
+		for _, match := range matches[1:] {
+			if match != "" {
+				return match, platformName, nil
+			}
 		}
 	}
 
@@ -67,11 +73,25 @@ func ParseSocialAccountURL(url string) (
 
 // ParseVkClipURL извлекает owner_id и clip_id из URL
 func ParseVkClipURL(url string) (int, int, error) {
-	re := regexp.MustCompile(`clip(-?\d+)_(\d+)`)
+	return parseVkVideoID(url, vkClipRe, "clip")
+}
+
+// ParseVkVideoURL извлекает owner_id и id клипа или видео: vk.com/clip-1_2,
+// vk.com/video-1_2, vk.com/video?z=video-1_2, vkvideo.ru/video-1_2
+func ParseVkVideoURL(url string) (int, int, error) {
+	return parseVkVideoID(url, vkVideoRe, "video")
+}
+
+var (
+	vkClipRe  = regexp.MustCompile(`clip(-?\d+)_(\d+)`)
+	vkVideoRe = regexp.MustCompile(`(?:clip|video)(-?\d+)_(\d+)`)
+)
+
+func parseVkVideoID(url string, re *regexp.Regexp, kind string) (int, int, error) {
 	matches := re.FindStringSubmatch(url)
 
 	if len(matches) != 3 {
-		return 0, 0, fmt.Errorf("invalid clip URL format")
+		return 0, 0, fmt.Errorf("invalid %s URL format", kind)
 	}
 
 	ownerID, err := strconv.Atoi(matches[1])
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// UrlStatus итог парсинга ссылки, пишется в колонку статуса рядом с причиной
+type UrlStatus string
+
+const (
+	UrlStatusOK            UrlStatus = "ok"
+	UrlStatusNotFound      UrlStatus = "not_found"
+	UrlStatusPrivate       UrlStatus = "private"
+	UrlStatusUnsupported   UrlStatus = "unsupported"
+	UrlStatusRateLimited   UrlStatus = "rate_limited"
+	UrlStatusProviderError UrlStatus = "provider_error"
+)
+
+// ошибки провайдеров, по которым определяется статус ссылки
+var (
+	ErrContentNotFound = errors.New("content not found")
+	ErrContentPrivate  = errors.New("content is private")
+	ErrUnsupportedURL  = errors.New("unsupported or malformed link")
+	ErrRateLimited     = errors.New("provider rate limit exceeded")
+)
+
+// maxStatusReason длина причины в ячейке, тела ответов API бывают огромными
+const maxStatusReason = 200
+
+// HTTPStatusError ответ провайдера с кодом, отличным от 200
+type HTTPStatusError struct {
+	Code int
+	Body string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("err http code: %d, body: %s", e.Code, e.Body)
+}
+
+// ClassifyError определяет статус ссылки по ошибке провайдера
+func ClassifyError(err error) UrlStatus {
+	switch {
+	case err == nil:
+		return UrlStatusOK
+	case errors.Is(err, ErrUnsupportedURL):
+		return UrlStatusUnsupported
+	case errors.Is(err, ErrContentNotFound):
+		return UrlStatusNotFound
+	case errors.Is(err, ErrContentPrivate):
+		return UrlStatusPrivate
+	case errors.Is(err, ErrRateLimited):
+		return UrlStatusRateLimited
+	}
+
+	code := 0
+	var httpErr *HTTPStatusError
+	var apiErr *googleapi.Error
+	switch {
+	case errors.As(err, &httpErr):
+		code = httpErr.Code
+	case errors.As(err, &apiErr):
+		code = apiErr.Code
+	}
+
+	switch code {
+	case http.StatusTooManyRequests:
+		return UrlStatusRateLimited
+	case http.StatusNotFound, http.StatusGone:
+		return UrlStatusNotFound
+	}
+
+	// провайдеры RapidAPI часто отвечают 200 с текстом ошибки
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "private"):
+		return UrlStatusPrivate
+	case strings.Contains(msg, "not found"), strings.Contains(msg, "not exist"), strings.Contains(msg, "deleted"):
+		return UrlStatusNotFound
+	case strings.Contains(msg, "rate limit"), strings.Contains(msg, "too many requests"):
+		return UrlStatusRateLimited
+	default:
+		return UrlStatusProviderError
+	}
+}
+
+// SetDefaultStatus помечает строку, собранную без ошибки, статусом ok
+func (r *ResultRowUrl) SetDefaultStatus() {
+	if r.Status == "" {
+		r.Status = UrlStatusOK
+	}
+}
+
+// Failed строка собрана с ошибкой: метрик в ней нет, есть только статус и причина
+func (r *ResultRowUrl) Failed() bool {
+	return r.Status != "" && r.Status != UrlStatusOK
+}
+
+// SharesCell значение ячейки репостов: пусто, если платформа их не отдаёт
+func (r *ResultRowUrl) SharesCell() interface{} {
+	if r.NoShares {
+		return ""
+	}
+
+	return r.Shares
+}
+
+// CountStatuses считает строки пачки по статусам, пропущенные (nil) не учитываются
+func CountStatuses(results []*ResultRowUrl) map[UrlStatus]int {
+	counts := make(map[UrlStatus]int)
+	for _, result := range results {
+		if result != nil {
+			counts[result.Status]++
+		}
+	}
+
+	return counts
+}
+
+// FailedResultRow пустая строка результата со статусом и причиной ошибки
+func FailedResultRow(url string, err error) *ResultRowUrl {
+	row := EmptyResultRow(url)
+	row.Status = ClassifyError(err)
+	row.Reason = statusReason(err)
+
+	return row
+}
+
+func statusReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	reason := []rune(strings.Join(strings.Fields(err.Error()), " "))
+	if len(reason) > maxStatusReason {
+		return string(reason[:maxStatusReason]) + "…"
+	}
+
+	return string(reason)
+}
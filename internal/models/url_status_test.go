@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestClassifyError(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want UrlStatus
+	}{
+		{name: "nil", args: args{err: nil}, want: UrlStatusOK},
+		{name: "not found sentinel", args: args{err: fmt.Errorf("%w: clip", ErrContentNotFound)}, want: UrlStatusNotFound},
+		{name: "private sentinel", args: args{err: fmt.Errorf("vk: %w", ErrContentPrivate)}, want: UrlStatusPrivate},
+		{name: "malformed link", args: args{err: fmt.Errorf("%w: bad id", ErrUnsupportedURL)}, want: UrlStatusUnsupported},
+		{name: "rapid 429", args: args{err: &HTTPStatusError{Code: 429, Body: "Too many"}}, want: UrlStatusRateLimited},
+		{name: "rapid 404", args: args{err: fmt.Errorf("wrapped: %w", &HTTPStatusError{Code: 404})}, want: UrlStatusNotFound},
+		{name: "google 429", args: args{err: &googleapi.Error{Code: 429}}, want: UrlStatusRateLimited},
+		{name: "private in body", args: args{err: &HTTPStatusError{Code: 400, Body: `{"message":"This account is private"}`}}, want: UrlStatusPrivate},
+		{name: "rapid 500", args: args{err: &HTTPStatusError{Code: 500, Body: "oops"}}, want: UrlStatusProviderError},
+		{name: "other", args: args{err: errors.New("failed to parse JSON")}, want: UrlStatusProviderError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.args.err); got != tt.want {
+				t.Errorf("ClassifyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailedResultRow(t *testing.T) {
+	row := FailedResultRow("https://vk.com/clip1_2", &HTTPStatusError{Code: 502, Body: strings.Repeat("x\n", 500)})
+
+	if row.Status != UrlStatusProviderError {
+		t.Errorf("FailedResultRow().Status = %v, want %v", row.Status, UrlStatusProviderError)
+	}
+	if n := len([]rune(row.Reason)); n != maxStatusReason+1 || strings.Contains(row.Reason, "\n") {
+		t.Errorf("FailedResultRow().Reason length = %d, want single line of %d", n, maxStatusReason+1)
+	}
+}
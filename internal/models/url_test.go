@@ -121,6 +121,33 @@ func Test_parseSocialAccountURL(t *testing.T) {
 			wantAccount:     "-41699827",
 			wantErr:         false,
 		},
+		{
+			name: "case 13",
+			args: args{
+				url: "https://www.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw/videos",
+			},
+			wantParsingType: YoutubeParsingType,
+			wantAccount:     "UCuAXFkgsw1L7xaCfnd5JJOw",
+			wantErr:         false,
+		},
+		{
+			name: "case 14",
+			args: args{
+				url: "https://m.youtube.com/c/mnogadetna",
+			},
+			wantParsingType: YoutubeParsingType,
+			wantAccount:     "mnogadetna",
+			wantErr:         false,
+		},
+		{
+			name: "case 15",
+			args: args{
+				url: "https://youtube.com/user/mnogadetna?sub_confirmation=1",
+			},
+			wantParsingType: YoutubeParsingType,
+			wantAccount:     "mnogadetna",
+			wantErr:         false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -177,6 +204,54 @@ func TestParseVkClipURL(t *testing.T) {
 	}
 }
 
+func TestParseVkVideoURL(t *testing.T) {
+	type args struct {
+		url string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		want1   int
+		wantErr bool
+	}{
+		{name: "clip", args: args{url: "https://vk.com/clip-235319600_456239017"}, want: -235319600, want1: 456239017},
+		{name: "video", args: args{url: "https://vk.com/video-123_456"}, want: -123, want1: 456},
+		{name: "user video", args: args{url: "https://vk.com/video123_456"}, want: 123, want1: 456},
+		{name: "video in query", args: args{url: "https://vk.com/video?z=video-123_456%2Fpl_cat_trends"}, want: -123, want1: 456},
+		{name: "vkvideo.ru", args: args{url: "https://vkvideo.ru/video-123_456?list=ln-abc"}, want: -123, want1: 456},
+		{name: "video list", args: args{url: "https://vk.com/videos-123"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, err := ParseVkVideoURL(tt.args.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseVkVideoURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseVkVideoURL() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("ParseVkVideoURL() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestVkVideoURLs(t *testing.T) {
+	const url = "https://vkvideo.ru/video-123_456"
+
+	if got := ParsingTypeByUrl(url); got != VKGroupParsingType {
+		t.Errorf("ParsingTypeByUrl() = %v, want %v", got, VKGroupParsingType)
+	}
+
+	canonical, ok := Canonicalize(url)
+	if !ok || canonical.URL != "https://vk.com/video-123_456" {
+		t.Errorf("Canonicalize() = %+v, %v", canonical, ok)
+	}
+}
+
 func TestExtractVKPostID(t *testing.T) {
 	type args struct {
 		url string
@@ -169,4 +169,6 @@ type AccountInfo struct {
 	ParsingType    ParsingType
 	AccountUrl     string
 	Count          int
+	// Window окно дат публикации; пагинация останавливается, когда лента уходит старше окна
+	Window DateWindow
 }
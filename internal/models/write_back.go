@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"inst_parser/internal/utils"
+)
+
+// MetricColumn метрика, которую режим записи в исходную строку обновляет в листе
+type MetricColumn string
+
+const (
+	MetricViews       MetricColumn = "views"
+	MetricLikes       MetricColumn = "likes"
+	MetricComments    MetricColumn = "comments"
+	MetricShares      MetricColumn = "shares"
+	MetricER          MetricColumn = "er"
+	MetricVirality    MetricColumn = "virality"
+	MetricParsingDate MetricColumn = "parsing_date"
+	MetricPublishDate MetricColumn = "publish_date"
+	MetricStatus      MetricColumn = "status"
+	MetricReason      MetricColumn = "reason"
+	MetricCanonical   MetricColumn = "canonical_url"
+	MetricContentType MetricColumn = "content_type"
+	MetricDuration    MetricColumn = "duration"
+	MetricViews24h    MetricColumn = "views_24h"
+	MetricViews7d     MetricColumn = "views_7d"
+)
+
+// metricsOrder порядок ячеек в запросе на запись
+var metricsOrder = []MetricColumn{
+	MetricViews,
+	MetricLikes,
+	MetricComments,
+	MetricShares,
+	MetricER,
+	MetricVirality,
+	MetricParsingDate,
+	MetricPublishDate,
+	MetricStatus,
+	MetricReason,
+	MetricCanonical,
+	MetricContentType,
+	MetricDuration,
+	MetricViews24h,
+	MetricViews7d,
+}
+
+// MetricColumns 1-based индексы колонок метрик в листе
+type MetricColumns map[MetricColumn]int
+
+// MetricColumnByHeader определяет метрику по заголовку колонки, как findColumns ищет колонку ссылок
+func MetricColumnByHeader(header string) (MetricColumn, bool) {
+	value := strings.ToLower(strings.TrimSpace(header))
+
+	isViews := strings.Contains(value, "охват") || strings.Contains(value, "просмотр")
+
+	switch {
+	// прирост просмотров проверяем раньше самих просмотров: "Просмотры +24ч", "Охват за неделю"
+	case isViews && (strings.Contains(value, "24") || strings.Contains(value, "сутки")):
+		return MetricViews24h, true
+	case isViews && (strings.Contains(value, "+7") || strings.Contains(value, "7д") ||
+		strings.Contains(value, "7d") || strings.Contains(value, "недел")):
+		return MetricViews7d, true
+	case isViews:
+		return MetricViews, true
+	case strings.Contains(value, "лайк"):
+		return MetricLikes, true
+	case strings.Contains(value, "коммент"):
+		return MetricComments, true
+	case strings.Contains(value, "репост"):
+		return MetricShares, true
+	case value == "er" || strings.HasPrefix(value, "er "):
+		return MetricER, true
+	case strings.Contains(value, "вирал"):
+		return MetricVirality, true
+	// "Дата парсинга" не распознаём: findColumns по слову "парсинг" ищет колонку-чекбокс
+	case strings.Contains(value, "дата обновления"):
+		return MetricParsingDate, true
+	case strings.Contains(value, "дата публикации"):
+		return MetricPublishDate, true
+	case strings.Contains(value, "статус"):
+		return MetricStatus, true
+	case strings.Contains(value, "причина"):
+		return MetricReason, true
+	case strings.Contains(value, "каноническ"), strings.Contains(value, "итоговая ссылка"):
+		return MetricCanonical, true
+	case strings.Contains(value, "тип контента"), strings.Contains(value, "формат"):
+		return MetricContentType, true
+	case strings.Contains(value, "длительност"), strings.Contains(value, "хронометраж"):
+		return MetricDuration, true
+	default:
+		return "", false
+	}
+}
+
+// CellValue значение одной ячейки листа
+type CellValue struct {
+	Range string
+	Value interface{}
+}
+
+// WriteBackCells ячейки метрик результата в строке row листа sheetName.
+// Ненайденные в листе метрики пропускаются. У строки с ошибкой пишутся только статус
+// и причина, чтобы неудачный запрос не затёр нулями метрики прошлого парсинга.
+func (c MetricColumns) WriteBackCells(sheetName string, row int, result *ResultRowUrl) []*CellValue {
+	if result == nil || row <= 0 {
+		return nil
+	}
+
+	values := map[MetricColumn]interface{}{
+		MetricViews:       result.Views,
+		MetricLikes:       result.Likes,
+		MetricComments:    result.Comments,
+		MetricShares:      result.SharesCell(),
+		MetricER:          result.ER,
+		MetricVirality:    result.Virality,
+		MetricParsingDate: result.ParsingDate,
+		MetricPublishDate: result.PublishDate,
+		MetricStatus:      string(result.Status),
+		MetricReason:      result.Reason,
+		MetricCanonical:   result.CanonicalURL,
+		MetricContentType: result.ContentType,
+		MetricDuration:    result.Duration,
+		MetricViews24h:    result.ViewsGain24h,
+		MetricViews7d:     result.ViewsGain7d,
+	}
+
+	cells := make([]*CellValue, 0, len(c))
+	for _, metric := range metricsOrder {
+		column := c[metric]
+		if column <= 0 {
+			continue
+		}
+		if result.Failed() && metric != MetricStatus && metric != MetricReason {
+			continue
+		}
+		value := values[metric]
+
+		cells = append(cells, &CellValue{
+			Range: fmt.Sprintf("%s!%s%d", sheetName, utils.ColumnLetter(column), row),
+			Value: value,
+		})
+	}
+
+	return cells
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetricColumnByHeader(t *testing.T) {
+	type args struct {
+		header string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   MetricColumn
+		wantOk bool
+	}{
+		{name: "views", args: args{header: " Охват факт "}, want: MetricViews, wantOk: true},
+		{name: "views gain day", args: args{header: "Просмотры +24ч"}, want: MetricViews24h, wantOk: true},
+		{name: "views gain week", args: args{header: "Охват за неделю"}, want: MetricViews7d, wantOk: true},
+		{name: "likes", args: args{header: "Лайки"}, want: MetricLikes, wantOk: true},
+		{name: "comments", args: args{header: "Комментарии"}, want: MetricComments, wantOk: true},
+		{name: "shares", args: args{header: "Репосты"}, want: MetricShares, wantOk: true},
+		{name: "er", args: args{header: "ER"}, want: MetricER, wantOk: true},
+		{name: "er with formula", args: args{header: "ER (likes+shares+comments)/views*100"}, want: MetricER, wantOk: true},
+		{name: "parsing date", args: args{header: "Дата обновления"}, want: MetricParsingDate, wantOk: true},
+		{name: "parsing date clashes with checkbox column", args: args{header: "Дата парсинга"}},
+		{name: "publish date", args: args{header: "Дата публикации"}, want: MetricPublishDate, wantOk: true},
+		{name: "status", args: args{header: "Статус парсинга"}, want: MetricStatus, wantOk: true},
+		{name: "reason", args: args{header: "Причина"}, want: MetricReason, wantOk: true},
+		{name: "url column", args: args{header: "Ссылка на видео"}, wantOk: false},
+		{name: "word containing er", args: args{header: "Performer"}, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MetricColumnByHeader(tt.args.header)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("MetricColumnByHeader() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMetricColumns_WriteBackCells(t *testing.T) {
+	type args struct {
+		row    int
+		result *ResultRowUrl
+	}
+	columns := MetricColumns{
+		MetricViews:       3,
+		MetricLikes:       4,
+		MetricParsingDate: 28,
+		MetricStatus:      29,
+		MetricReason:      30,
+	}
+	tests := []struct {
+		name string
+		args args
+		want []*CellValue
+	}{
+		{
+			name: "case 1",
+			args: args{
+				row:    5,
+				result: &ResultRowUrl{Views: 100, Likes: 7, Comments: 2, ParsingDate: "01.10.2025 18:01"},
+			},
+			want: []*CellValue{
+				{Range: "Лист1!C5", Value: int64(100)},
+				{Range: "Лист1!D5", Value: int64(7)},
+				{Range: "Лист1!AB5", Value: "01.10.2025 18:01"},
+				{Range: "Лист1!AC5", Value: ""},
+				{Range: "Лист1!AD5", Value: ""},
+			},
+		},
+		{
+			name: "failed fetch keeps metrics",
+			args: args{
+				row:    5,
+				result: FailedResultRow("https://vk.com/clip-1_2", ErrRateLimited),
+			},
+			want: []*CellValue{
+				{Range: "Лист1!AC5", Value: string(UrlStatusRateLimited)},
+				{Range: "Лист1!AD5", Value: ErrRateLimited.Error()},
+			},
+		},
+		{
+			name: "skipped url",
+			args: args{row: 5},
+			want: nil,
+		},
+		{
+			name: "unknown row",
+			args: args{result: &ResultRowUrl{Views: 100}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columns.WriteBackCells("Лист1", tt.args.row, tt.args.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WriteBackCells() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package checkpoint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+// Store хранит контрольные точки парсинга: по файлу на задачу, в который после
+// каждой записанной в таблицу пачки дописывается строка JSON с её ссылками и статусами.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+type batch struct {
+	Urls     []string                 `json:"urls"`
+	Statuses map[models.UrlStatus]int `json:"statuses,omitempty"`
+	At       time.Time                `json:"at"`
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoints dir: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Parsed возвращает ссылки, уже записанные задачей, и счётчики их статусов:
+// перезапущенная задача собирает сводку заново и учитывает в ней пропущенные ссылки.
+// Нет контрольной точки — пустой список.
+func (s *Store) Parsed(jobID string) ([]string, map[models.UrlStatus]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path(jobID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	var urls []string
+	statuses := make(map[models.UrlStatus]int)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var b batch
+		if err := json.Unmarshal(scanner.Bytes(), &b); err != nil {
+			// недописанная строка при падении процесса — пачка будет обработана заново
+			continue
+		}
+		urls = append(urls, b.Urls...)
+		for status, n := range b.Statuses {
+			statuses[status] += n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	return urls, statuses, nil
+}
+
+// Append фиксирует пачку ссылок, записанную в таблицу, со счётчиками их статусов
+func (s *Store) Append(jobID string, urls []string, statuses map[models.UrlStatus]int) error {
+	data, err := json.Marshal(batch{Urls: urls, Statuses: statuses, At: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path(jobID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if err = file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет контрольную точку завершённой задачи
+func (s *Store) Delete(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(jobID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) path(jobID string) string {
+	return filepath.Join(s.dir, filepath.Base(jobID)+".jsonl")
+}
@@ -0,0 +1,62 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"inst_parser/internal/models"
+)
+
+func TestStore_AppendParsedDelete(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	if urls, _, err := s.Parsed("job"); err != nil || len(urls) != 0 {
+		t.Fatalf("Parsed() without checkpoint = %v, %v, want empty", urls, err)
+	}
+
+	batches := []struct {
+		urls     []string
+		statuses map[models.UrlStatus]int
+	}{
+		{urls: []string{"a", "b"}, statuses: map[models.UrlStatus]int{models.UrlStatusOK: 1, models.UrlStatusNotFound: 1}},
+		{urls: []string{"c"}, statuses: map[models.UrlStatus]int{models.UrlStatusOK: 1}},
+	}
+	for _, batch := range batches {
+		if err = s.Append("job", batch.urls, batch.statuses); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	// обрезанная строка после падения процесса не должна ломать загрузку
+	f, _ := os.OpenFile(filepath.Join(dir, "job.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"urls":["d"`)
+	f.Close()
+
+	urls, statuses, err := s.Parsed("job")
+	if err != nil {
+		t.Fatalf("Parsed() error = %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(urls, want) {
+		t.Errorf("Parsed() = %v, want %v", urls, want)
+	}
+	if want := (map[models.UrlStatus]int{models.UrlStatusOK: 2, models.UrlStatusNotFound: 1}); !reflect.DeepEqual(statuses, want) {
+		t.Errorf("Parsed() statuses = %v, want %v", statuses, want)
+	}
+
+	if err = s.Delete("job"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if urls, _, _ = s.Parsed("job"); len(urls) != 0 {
+		t.Errorf("Parsed() after Delete() = %v, want empty", urls)
+	}
+	if err = s.Delete("job"); err != nil {
+		t.Errorf("Delete() twice error = %v", err)
+	}
+}
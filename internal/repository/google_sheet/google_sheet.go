@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"inst_parser/internal/config"
+	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
 
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
@@ -50,6 +53,7 @@ func NewRepository(cfg config.GoogleDriveCredentials) *Repository {
 }
 
 func (r *Repository) InsertData(
+	ctx context.Context,
 	spreadsheetID,
 	sheetName,
 	rangeData string,
@@ -67,10 +71,150 @@ func (r *Repository) InsertData(
 		spreadsheetID,
 		fmt.Sprintf("%s!%s", sheetName, rangeData),
 		valueRange,
-	).ValueInputOption("USER_ENTERED").Do()
+	).ValueInputOption("USER_ENTERED").Context(ctx).Do()
 
 	if err != nil {
-		return fmt.Errorf("failed to insert data: %v", err)
+		return fmt.Errorf("failed to insert data: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertData записывает строки в лист по ссылке на ролик из колонки keyColumn
+// (0-based от начала диапазона): строки уже записанных роликов перезаписываются,
+// новые ролики добавляются в конец. Строки с ошибкой (failed) записанные ролики не трогают.
+func (r *Repository) UpsertData(
+	ctx context.Context,
+	spreadsheetID,
+	sheetName,
+	rangeData string,
+	keyColumn int,
+	data [][]interface{},
+	failed []bool,
+) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	firstColumn, _, ok := strings.Cut(rangeData, ":")
+	if !ok || utils.ColumnNumber(firstColumn) == 0 {
+		return fmt.Errorf("invalid range %s", rangeData)
+	}
+	keyLetter := utils.ColumnLetter(utils.ColumnNumber(firstColumn) + keyColumn)
+
+	resp, err := r.SheetsService.Spreadsheets.Values.Get(
+		spreadsheetID,
+		fmt.Sprintf("%s!%s:%s", sheetName, keyLetter, keyLetter),
+	).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", sheetName, err)
+	}
+
+	existing := make([]string, len(resp.Values))
+	for i, row := range resp.Values {
+		if len(row) > 0 {
+			existing[i] = fmt.Sprint(row[0])
+		}
+	}
+
+	updates, appends := models.PlanUpsert(existing, keyColumn, data, failed)
+
+	if len(updates) > 0 {
+		ranges := make([]*sheets.ValueRange, 0, len(updates))
+		for _, update := range updates {
+			ranges = append(ranges, &sheets.ValueRange{
+				Range:  update.Range(sheetName, firstColumn),
+				Values: [][]interface{}{update.Values},
+			})
+		}
+
+		_, err = r.SheetsService.Spreadsheets.Values.BatchUpdate(
+			spreadsheetID,
+			&sheets.BatchUpdateValuesRequest{
+				ValueInputOption: "USER_ENTERED",
+				Data:             ranges,
+			},
+		).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to update rows: %w", err)
+		}
+	}
+
+	if len(appends) == 0 {
+		return nil
+	}
+
+	return r.InsertData(ctx, spreadsheetID, sheetName, rangeData, appends)
+}
+
+// EnsureSheet создаёт лист с заголовками в первой строке, если его ещё нет
+func (r *Repository) EnsureSheet(
+	ctx context.Context,
+	spreadsheetID,
+	sheetName string,
+	headers []interface{},
+) error {
+	spreadsheet, err := r.SheetsService.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Title == sheetName {
+			return nil
+		}
+	}
+
+	_, err = r.SheetsService.Spreadsheets.BatchUpdate(spreadsheetID, &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{{
+			AddSheet: &sheets.AddSheetRequest{
+				Properties: &sheets.SheetProperties{Title: sheetName},
+			},
+		}},
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to create sheet %s: %w", sheetName, err)
+	}
+
+	_, err = r.SheetsService.Spreadsheets.Values.Update(
+		spreadsheetID,
+		fmt.Sprintf("%s!A1", sheetName),
+		&sheets.ValueRange{Values: [][]interface{}{headers}},
+	).ValueInputOption("RAW").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to write headers to %s: %w", sheetName, err)
+	}
+
+	return nil
+}
+
+// UpdateCells перезаписывает ячейки одним запросом
+func (r *Repository) UpdateCells(
+	ctx context.Context,
+	spreadsheetID string,
+	cells []*models.CellValue,
+) error {
+	if len(cells) == 0 {
+		return nil
+	}
+
+	data := make([]*sheets.ValueRange, 0, len(cells))
+	for _, cell := range cells {
+		data = append(data, &sheets.ValueRange{
+			Range:  cell.Range,
+			Values: [][]interface{}{{cell.Value}},
+		})
+	}
+
+	_, err := r.SheetsService.Spreadsheets.Values.BatchUpdate(
+		spreadsheetID,
+		&sheets.BatchUpdateValuesRequest{
+			ValueInputOption: "USER_ENTERED",
+			Data:             data,
+		},
+	).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to update cells: %w", err)
 	}
 
 	return nil
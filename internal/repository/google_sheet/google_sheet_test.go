@@ -1,6 +1,7 @@
 package google_sheet
 
 import (
+	"context"
 	"testing"
 
 	"inst_parser/internal/config"
@@ -40,7 +41,7 @@ func TestService_InsertData(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := srv
-			if err := s.InsertData(tt.args.spreadsheetID, tt.args.sheetName, tt.args.data); (err != nil) != tt.wantErr {
+			if err := s.InsertData(context.Background(), tt.args.spreadsheetID, tt.args.sheetName, tt.args.data); (err != nil) != tt.wantErr {
 				t.Errorf("InsertData() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -0,0 +1,241 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+// compactInterval как часто при дописывании история очищается от снимков старше срока хранения
+const compactInterval = 24 * time.Hour
+
+// Store локальная история метрик роликов. Снимки дописываются в файл строками JSON,
+// при открытии файл читается в индекс по ключу контента. Снимки старше retention
+// отбрасываются при открытии и раз в compactInterval при дописывании, файл при этом
+// перезаписывается, поэтому ни файл, ни индекс не растут бесконечно.
+type Store struct {
+	mu          sync.RWMutex
+	path        string
+	retention   time.Duration // 0 — хранить всё
+	file        *os.File
+	series      map[string][]*models.MetricSnapshot
+	nextCompact time.Time
+	now         func() time.Time
+}
+
+func NewStore(path string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history dir: %w", err)
+	}
+
+	s := &Store{
+		path:      path,
+		retention: retention,
+		series:    make(map[string][]*models.MetricSnapshot),
+		now:       time.Now,
+	}
+
+	dropped, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if dropped > 0 {
+		if err = s.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.file == nil {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open history: %w", err)
+		}
+		s.file = file
+	}
+	s.nextCompact = s.now().Add(compactInterval)
+
+	return s, nil
+}
+
+// load читает файл в индекс, пропуская устаревшие снимки. Возвращает число пропущенных.
+func (s *Store) load() (int, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	cutoff := s.cutoff()
+	dropped := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var snapshot models.MetricSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			// недописанная строка при падении процесса — пропускаем
+			continue
+		}
+		if snapshot.At.Before(cutoff) {
+			dropped++
+			continue
+		}
+		s.add(&snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	return dropped, nil
+}
+
+// Append дописывает снимки в историю
+func (s *Store) Append(snapshots []*models.MetricSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var buf []byte
+	for _, snapshot := range snapshots {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(buf); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	for _, snapshot := range snapshots {
+		s.add(snapshot)
+	}
+
+	if s.retention <= 0 || s.now().Before(s.nextCompact) {
+		return nil
+	}
+	s.nextCompact = s.now().Add(compactInterval)
+	if s.prune() == 0 {
+		return nil
+	}
+
+	return s.rewrite()
+}
+
+// Series снимки ролика по ключу контента от старых к новым
+func (s *Store) Series(key string) []*models.MetricSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.series[key])
+}
+
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// add кладёт снимок в индекс, сохраняя порядок по времени
+func (s *Store) add(snapshot *models.MetricSnapshot) {
+	series := s.series[snapshot.Key]
+	i := len(series)
+	for i > 0 && series[i-1].At.After(snapshot.At) {
+		i--
+	}
+	s.series[snapshot.Key] = slices.Insert(series, i, snapshot)
+}
+
+// cutoff снимки раньше этого момента устарели; без срока хранения — нулевое время
+func (s *Store) cutoff() time.Time {
+	if s.retention <= 0 {
+		return time.Time{}
+	}
+
+	return s.now().Add(-s.retention)
+}
+
+// prune убирает из индекса устаревшие снимки. Возвращает число убранных.
+func (s *Store) prune() int {
+	cutoff := s.cutoff()
+	dropped := 0
+
+	for key, series := range s.series {
+		i := slices.IndexFunc(series, func(snapshot *models.MetricSnapshot) bool {
+			return !snapshot.At.Before(cutoff)
+		})
+		switch i {
+		case 0:
+			continue
+		case -1:
+			dropped += len(series)
+			delete(s.series, key)
+		default:
+			dropped += i
+			// копия, чтобы не держать в памяти начало старого массива
+			s.series[key] = slices.Clone(series[i:])
+		}
+	}
+
+	return dropped
+}
+
+// rewrite заменяет файл снимками из индекса (компактизация), как journal.Rewrite
+func (s *Store) rewrite() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create history: %w", err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, series := range s.series {
+		for _, snapshot := range series {
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				tmp.Close()
+				return fmt.Errorf("failed to marshal snapshot: %w", err)
+			}
+			w.Write(data)
+			w.WriteByte('\n')
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close history: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace history: %w", err)
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+func TestStore_SeriesAfterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.history")
+
+	s, err := NewStore(path, 0)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	key := models.UpsertKey("https://www.instagram.com/reel/ABC/")
+	err = s.Append([]*models.MetricSnapshot{
+		{Key: key, At: at.Add(48 * time.Hour), Views: 300},
+		{Key: key, At: at, Views: 100},
+		{Key: "other", At: at, Views: 1},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err = s.Append([]*models.MetricSnapshot{{Key: key, At: at.Add(24 * time.Hour), Views: 200}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	s.Close()
+
+	// обрезанная строка после падения процесса не должна ломать загрузку
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"key":"` + key + `","views":`)
+	f.Close()
+
+	s, err = NewStore(path, 0)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	defer s.Close()
+
+	series := s.Series(key)
+	if len(series) != 3 {
+		t.Fatalf("Series() len = %d, want 3", len(series))
+	}
+	for i, want := range []int64{100, 200, 300} {
+		if series[i].Views != want {
+			t.Errorf("Series()[%d].Views = %d, want %d", i, series[i].Views, want)
+		}
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.history")
+	now := time.Now()
+
+	s, err := NewStore(path, 0)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	err = s.Append([]*models.MetricSnapshot{
+		{Key: "old", At: now.Add(-10 * 24 * time.Hour), Views: 1},
+		{Key: "mixed", At: now.Add(-9 * 24 * time.Hour), Views: 1},
+		{Key: "mixed", At: now.Add(-time.Hour), Views: 2},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	s.Close()
+
+	// при открытии устаревшие снимки отбрасываются и файл перезаписывается
+	s, err = NewStore(path, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	defer s.Close()
+
+	if got := s.Series("old"); len(got) != 0 {
+		t.Errorf("Series(old) len = %d, want 0", len(got))
+	}
+	if got := s.Series("mixed"); len(got) != 1 || got[0].Views != 2 {
+		t.Errorf("Series(mixed) = %+v, want only the fresh snapshot", got)
+	}
+	if lines := countLines(t, path); lines != 1 {
+		t.Errorf("history lines after open = %d, want 1", lines)
+	}
+
+	// при дописывании после интервала компактизации устаревают и снимки из памяти
+	s.now = func() time.Time { return now.Add(8 * 24 * time.Hour) }
+	if err = s.Append([]*models.MetricSnapshot{{Key: "new", At: now.Add(8 * 24 * time.Hour), Views: 3}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if got := s.Series("mixed"); len(got) != 0 {
+		t.Errorf("Series(mixed) len = %d, want 0 after compaction", len(got))
+	}
+	if lines := countLines(t, path); lines != 1 {
+		t.Errorf("history lines after compaction = %d, want 1", lines)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	return strings.Count(string(data), "\n")
+}
@@ -0,0 +1,144 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"inst_parser/internal/models"
+)
+
+// Journal файловый журнал задач очереди. Каждое изменение задачи дописывается
+// строкой JSON, при загрузке берётся последний снимок каждой задачи.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewJournal(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create journal dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	return &Journal{
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Save дописывает снимок задачи в журнал
+func (j *Journal) Save(job *models.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err = j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
+
+// Load читает журнал и возвращает последний снимок каждой задачи в порядке создания
+func (j *Journal) Load() ([]*models.Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	file, err := os.Open(j.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	jobs := make(map[string]*models.Job)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var job models.Job
+		if err := json.Unmarshal(scanner.Bytes(), &job); err != nil {
+			// недописанная строка при падении процесса — пропускаем
+			continue
+		}
+		jobs[job.ID] = &job
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	result := make([]*models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, job)
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].CreatedAt.Before(result[b].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// Rewrite заменяет журнал снимками переданных задач (компактизация)
+func (j *Journal) Rewrite(jobs []*models.Job) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create journal: %w", err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, job := range jobs {
+		data, err := json.Marshal(job)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+		w.Write(data)
+		w.WriteByte('\n')
+	}
+
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+
+	j.file.Close()
+	j.file, err = os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+
+	return nil
+}
+
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
@@ -0,0 +1,68 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+func TestJournal_LoadLatestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.journal")
+
+	j, err := NewJournal(path)
+	if err != nil {
+		t.Fatalf("NewJournal() error = %v", err)
+	}
+	defer j.Close()
+
+	created := time.Now()
+	first := &models.Job{QueueRequest: models.QueueRequest{ID: "1"}, State: models.JobStatePending, CreatedAt: created}
+	second := &models.Job{QueueRequest: models.QueueRequest{ID: "2"}, State: models.JobStatePending, CreatedAt: created.Add(time.Second)}
+
+	for _, job := range []*models.Job{first, second} {
+		if err = j.Save(job); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	first.State = models.JobStateRunning
+	first.Processed = 50
+	if err = j.Save(first); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// обрезанная строка после падения процесса не должна ломать загрузку
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"id":"1","state":"fin`)
+	f.Close()
+
+	jobs, err := j.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(jobs) != 2 || jobs[0].ID != "1" || jobs[1].ID != "2" {
+		t.Fatalf("Load() = %+v, want jobs 1 and 2 in creation order", jobs)
+	}
+	if jobs[0].State != models.JobStateRunning || jobs[0].Processed != 50 {
+		t.Errorf("Load() job 1 = %+v, want latest snapshot", jobs[0])
+	}
+
+	if err = j.Rewrite(jobs[:1]); err != nil {
+		t.Fatalf("Rewrite() error = %v", err)
+	}
+	if err = j.Save(second); err != nil {
+		t.Fatalf("Save() after Rewrite() error = %v", err)
+	}
+
+	jobs, err = j.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Errorf("Load() after Rewrite() = %d jobs, want 2", len(jobs))
+	}
+}
@@ -1,6 +1,7 @@
 package progress
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -12,6 +13,13 @@ import (
 
 const headerRow = 1
 
+const (
+	statusRunning     = "В процессе"
+	statusFinished    = "Завершено"
+	statusCancelled   = "Отменено"
+	statusInterrupted = "Прервано"
+)
+
 type Tracker struct {
 	sheetsService *sheets.Service
 }
@@ -25,9 +33,9 @@ func NewProgressTracker(sheetsService *sheets.Service) *Tracker {
 	}
 }
 
-func (pt *Tracker) EnsureProgressSheet(spreadsheetID string) error {
+func (pt *Tracker) EnsureProgressSheet(ctx context.Context, spreadsheetID string) error {
 	// Получаем информацию о таблице
-	spreadsheet, err := pt.sheetsService.Spreadsheets.Get(spreadsheetID).Do()
+	spreadsheet, err := pt.sheetsService.Spreadsheets.Get(spreadsheetID).Context(ctx).Do()
 	if err != nil {
 		return fmt.Errorf("failed to get spreadsheet: %w", err)
 	}
@@ -55,38 +63,35 @@ func (pt *Tracker) EnsureProgressSheet(spreadsheetID string) error {
 			Requests: []*sheets.Request{req},
 		}
 
-		_, err := pt.sheetsService.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Do()
+		_, err := pt.sheetsService.Spreadsheets.BatchUpdate(spreadsheetID, batchUpdateRequest).Context(ctx).Do()
 		if err != nil {
 			return fmt.Errorf("failed to create progress sheet: %w", err)
 		}
 
-		// Добавляем заголовки
-		if err := pt.writeHeaders(spreadsheetID); err != nil {
-			return err
-		}
 	}
 
-	return nil
+	// Добавляем заголовки (в старых листах нет колонки статуса)
+	return pt.writeHeaders(ctx, spreadsheetID)
 }
 
-func (pt *Tracker) writeHeaders(spreadsheetID string) error {
-	headers := []interface{}{"Начало парсинга", "Всего ссылок", "Обработано", "Конец парсинга"}
+func (pt *Tracker) writeHeaders(ctx context.Context, spreadsheetID string) error {
+	headers := []interface{}{"Начало парсинга", "Всего ссылок", "Обработано", "Конец парсинга", "Статус"}
 
 	valueRange := &sheets.ValueRange{
 		Values: [][]interface{}{headers},
 	}
 
-	rangeStr := fmt.Sprintf("%s!A%d:D%d", constants.ProgressTable, headerRow, headerRow)
+	rangeStr := fmt.Sprintf("%s!A%d:E%d", constants.ProgressTable, headerRow, headerRow)
 	_, err := pt.sheetsService.Spreadsheets.Values.Update(
 		spreadsheetID,
 		rangeStr,
 		valueRange,
-	).ValueInputOption("RAW").Do()
+	).ValueInputOption("RAW").Context(ctx).Do()
 
 	return err
 }
 
-func (pt *Tracker) StartParsing(spreadsheetID string, totalURLs int) (int, error) {
+func (pt *Tracker) StartParsing(ctx context.Context, spreadsheetID string, totalURLs int) (int, error) {
 	moscow, err := time.LoadLocation("Europe/Moscow")
 	if err != nil {
 		log.Printf("Warning: could not load Moscow timezone, using local: %v", err)
@@ -94,18 +99,18 @@ func (pt *Tracker) StartParsing(spreadsheetID string, totalURLs int) (int, error
 	}
 	startTime := time.Now().In(moscow).Format(time.DateTime)
 
-	row := []interface{}{startTime, totalURLs, 0, ""}
+	row := []interface{}{startTime, totalURLs, 0, "", statusRunning}
 	valueRange := &sheets.ValueRange{
 		Values: [][]interface{}{row},
 	}
 
 	// Добавляем новую строку после заголовка
-	rangeStr := fmt.Sprintf("%s!A2:D2", constants.ProgressTable)
+	rangeStr := fmt.Sprintf("%s!A2:E2", constants.ProgressTable)
 	_, err = pt.sheetsService.Spreadsheets.Values.Update(
 		spreadsheetID,
 		rangeStr,
 		valueRange,
-	).ValueInputOption("RAW").Do()
+	).ValueInputOption("RAW").Context(ctx).Do()
 
 	if err != nil {
 		return 0, fmt.Errorf("failed to start parsing progress: %w", err)
@@ -114,7 +119,7 @@ func (pt *Tracker) StartParsing(spreadsheetID string, totalURLs int) (int, error
 	return 2, nil // Возвращаем номер строки
 }
 
-func (pt *Tracker) UpdateProgress(spreadsheetID string, row, progress int) error {
+func (pt *Tracker) UpdateProgress(ctx context.Context, spreadsheetID string, row, progress int) error {
 	valueRange := &sheets.ValueRange{
 		Values: [][]interface{}{{progress}},
 	}
@@ -124,12 +129,26 @@ func (pt *Tracker) UpdateProgress(spreadsheetID string, row, progress int) error
 		spreadsheetID,
 		rangeStr,
 		valueRange,
-	).ValueInputOption("RAW").Do()
+	).ValueInputOption("RAW").Context(ctx).Do()
 
 	return err
 }
 
-func (pt *Tracker) FinishParsing(spreadsheetID string, row int) error {
+func (pt *Tracker) FinishParsing(ctx context.Context, spreadsheetID string, row int) error {
+	return pt.finish(ctx, spreadsheetID, row, statusFinished)
+}
+
+// CancelParsing отмечает в листе прогресса, что парсинг был отменён
+func (pt *Tracker) CancelParsing(ctx context.Context, spreadsheetID string, row int) error {
+	return pt.finish(ctx, spreadsheetID, row, statusCancelled)
+}
+
+// InterruptParsing отмечает в листе прогресса, что парсинг прерван остановкой сервиса
+func (pt *Tracker) InterruptParsing(ctx context.Context, spreadsheetID string, row int) error {
+	return pt.finish(ctx, spreadsheetID, row, statusInterrupted)
+}
+
+func (pt *Tracker) finish(ctx context.Context, spreadsheetID string, row int, status string) error {
 	moscow, err := time.LoadLocation("Europe/Moscow")
 	if err != nil {
 		log.Printf("Warning: could not load Moscow timezone, using local: %v", err)
@@ -138,15 +157,15 @@ func (pt *Tracker) FinishParsing(spreadsheetID string, row int) error {
 	endTime := time.Now().In(moscow).Format(time.DateTime)
 
 	valueRange := &sheets.ValueRange{
-		Values: [][]interface{}{{endTime}},
+		Values: [][]interface{}{{endTime, status}},
 	}
 
-	rangeStr := fmt.Sprintf("%s!D%d", constants.ProgressTable, row)
+	rangeStr := fmt.Sprintf("%s!D%d:E%d", constants.ProgressTable, row, row)
 	_, err = pt.sheetsService.Spreadsheets.Values.Update(
 		spreadsheetID,
 		rangeStr,
 		valueRange,
-	).ValueInputOption("RAW").Do()
+	).ValueInputOption("RAW").Context(ctx).Do()
 
 	return err
 }
@@ -8,16 +8,18 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"inst_parser/internal/constants"
 	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
 
 	"golang.org/x/time/rate"
 )
 
 type vkClipInfoProvider interface {
-	ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error)
+	ClipInfo(ctx context.Context, ownerID, clipID int) (*models.VKClipInfo, error)
 }
 
 type Repository struct {
@@ -48,13 +50,34 @@ func NewRepository(
 	}
 }
 
-func (r *Repository) GetInstagramReelsInfoForAccount(info *models.AccountInfo) ([]*models.InstagramReelInfo, error) {
+// InstagramConcurrency сколько запросов к Instagram имеет смысл выполнять одновременно
+func (r *Repository) InstagramConcurrency() int {
+	return r.instagramLimiter.Burst()
+}
+
+// TiktokConcurrency сколько запросов к TikTok имеет смысл выполнять одновременно
+func (r *Repository) TiktokConcurrency() int {
+	return r.tiktokLimiter.Burst()
+}
+
+// GetInstagramReelsInfoForAccount последние info.Count reels аккаунта
+func (r *Repository) GetInstagramReelsInfoForAccount(ctx context.Context, info *models.AccountInfo) ([]*models.InstagramReelInfo, error) {
+	return r.instagramFeed(ctx, info, constants.RapidRealTimeInstagramScraperUserReels)
+}
+
+// GetInstagramPostsInfoForAccount последние info.Count публикаций всей ленты аккаунта: reels, фото и карусели
+func (r *Repository) GetInstagramPostsInfoForAccount(ctx context.Context, info *models.AccountInfo) ([]*models.InstagramReelInfo, error) {
+	return r.instagramFeed(ctx, info, constants.RapidRealTimeInstagramScraperUserPosts)
+}
+
+func (r *Repository) instagramFeed(ctx context.Context, info *models.AccountInfo, feedURL string) ([]*models.InstagramReelInfo, error) {
 	reels := make([]*models.InstagramReelInfo, 0, info.Count)
 	var maxID string
 
 	for len(reels) < info.Count {
 		apiResp, err := r.getRapidRealTimeInstagramScraperUserReels(
-			getRapidRealTimeInstagramScraperUserReelsEndpoint(info.Identification, maxID),
+			ctx,
+			getRapidRealTimeInstagramScraperFeedEndpoint(feedURL, info.Identification, maxID),
 		)
 		if err != nil {
 			return []*models.InstagramReelInfo{models.EmptyReelInfo(info.AccountUrl)},
@@ -67,17 +90,25 @@ func (r *Repository) GetInstagramReelsInfoForAccount(info *models.AccountInfo) (
 		}
 
 		// Конвертируем и добавляем reels
+		var oldest time.Time
 		for _, apiReel := range apiResp.Data.Items {
 			if len(reels) >= info.Count {
 				break
 			}
 
-			reels = append(reels, models.ProcessInstagramReelResponse(&apiReel.Media, info.AccountUrl))
+			reel := models.ProcessInstagramReelResponse(&apiReel.Media, info.AccountUrl)
+			oldest = reel.Date
+			if !info.Window.Contains(reel.Date) {
+				continue
+			}
+
+			reels = append(reels, reel)
 		}
 
 		// Обновляем max_id для следующего запроса
 		maxID = apiResp.Data.PagingInfo.MaxID
-		if !apiResp.Data.PagingInfo.MoreAvailable {
+		// закреплённые публикации в начале ленты бывают старыми, поэтому смотрим на конец страницы
+		if !apiResp.Data.PagingInfo.MoreAvailable || info.Window.Passed(oldest) {
 			break
 		}
 	}
@@ -86,9 +117,55 @@ func (r *Repository) GetInstagramReelsInfoForAccount(info *models.AccountInfo) (
 }
 
 func (r *Repository) getRapidRealTimeInstagramScraperUserReels(
+	ctx context.Context,
 	endpoint string,
 ) (*models.GetRapidRealTimeInstagramScraperUserReelsResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	body, err := r.getRapidRealTimeInstagramScraper(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp models.GetRapidRealTimeInstagramScraperUserReelsResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Проверяем статус ответа (адаптируйте под вашу структуру)
+	if apiResp.Status != "ok" {
+		return nil, fmt.Errorf("API error: %s", apiResp.Message)
+	}
+
+	return &apiResp, nil
+}
+
+// GetInstagramProfile профиль аккаунта: подписчики, подписки, число публикаций, верификация
+func (r *Repository) GetInstagramProfile(ctx context.Context, username string) (*models.AccountProfile, error) {
+	params := url.Values{}
+	params.Add("username_or_id", username)
+
+	body, err := r.getRapidRealTimeInstagramScraper(
+		ctx,
+		fmt.Sprintf("%s?%s", constants.RapidRealTimeInstagramScraperUserInfo, params.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResp models.RealTimeScraperUserInfoResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Status != "ok" {
+		return nil, fmt.Errorf("API error: %s", apiResp.Message)
+	}
+
+	return apiResp.Data.ToProfile(), nil
+}
+
+// getRapidRealTimeInstagramScraper тело ответа real-time-instagram-scraper
+func (r *Repository) getRapidRealTimeInstagramScraper(ctx context.Context, endpoint string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	if err := r.instagramLimiter.Wait(ctx); err != nil {
@@ -129,25 +206,16 @@ func (r *Repository) getRapidRealTimeInstagramScraperUserReels(
 		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var apiResp models.GetRapidRealTimeInstagramScraperUserReelsResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	// Проверяем статус ответа (адаптируйте под вашу структуру)
-	if apiResp.Status != "ok" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Message)
-	}
-
-	return &apiResp, nil
+	return body, nil
 }
 
-func (r *Repository) GetVKClipsInfoForGroup(info *models.AccountInfo) ([]*models.VKClipInfo, error) {
+func (r *Repository) GetVKClipsInfoForGroup(ctx context.Context, info *models.AccountInfo) ([]*models.VKClipInfo, error) {
 	clips := make([]*models.VKClipInfo, 0, info.Count)
 
 	var cursor string
 	for len(clips) < info.Count {
 		apiResp, err := r.getVkClipsInfoForGroup(
+			ctx,
 			getVkUserClipsEndpoint(info.Identification, cursor),
 		)
 		if err != nil {
@@ -161,14 +229,20 @@ func (r *Repository) GetVKClipsInfoForGroup(info *models.AccountInfo) ([]*models
 		}
 
 		// Конвертируем и добавляем клипы
+		var oldest time.Time
 		for _, apiClip := range apiResp.Data.Clips {
 			if len(clips) >= info.Count {
 				break
 			}
 
+			oldest = utils.UnixTime(int64(apiClip.Date))
+			if !info.Window.Contains(oldest) {
+				continue
+			}
+
 			clipTmp := models.ProcessVkGroupClipResponse(apiClip, info.AccountUrl)
 
-			vkClipInfo, getVkClipInfoErr := r.vkClipInfoProvider.ClipInfo(apiClip.OwnerID, apiClip.ID)
+			vkClipInfo, getVkClipInfoErr := r.vkClipInfoProvider.ClipInfo(ctx, apiClip.OwnerID, apiClip.ID)
 			if getVkClipInfoErr != nil {
 				r.logger.Warn("failed to fetch vk clip info",
 					slog.Int("clip_id", apiClip.ID),
@@ -188,7 +262,7 @@ func (r *Repository) GetVKClipsInfoForGroup(info *models.AccountInfo) ([]*models
 
 		// Обновляем курсор для следующего запроса
 		cursor = apiResp.Data.Cursor
-		if cursor == "" {
+		if cursor == "" || info.Window.Passed(oldest) {
 			break
 		}
 	}
@@ -196,8 +270,8 @@ func (r *Repository) GetVKClipsInfoForGroup(info *models.AccountInfo) ([]*models
 	return clips, nil
 }
 
-func (r *Repository) getVkClipsInfoForGroup(endpoint string) (*models.RapidVkScraperUserClipsResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (r *Repository) getVkClipsInfoForGroup(ctx context.Context, endpoint string) (*models.RapidVkScraperUserClipsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	if err := r.vkLimiter.Wait(ctx); err != nil {
 		return nil, err
@@ -249,8 +323,8 @@ func (r *Repository) getVkClipsInfoForGroup(endpoint string) (*models.RapidVkScr
 	return &apiResp, nil
 }
 
-func (r *Repository) GetInstagramReelInfo(reelURL string) (*models.RealTimeScraperMediaInfoResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (r *Repository) GetInstagramReelInfo(ctx context.Context, reelURL string) (*models.RealTimeScraperMediaInfoResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	if err := r.instagramLimiter.Wait(ctx); err != nil {
@@ -291,7 +365,7 @@ func (r *Repository) GetInstagramReelInfo(reelURL string) (*models.RealTimeScrap
 	// Обрабатываем ответ
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("err http code: %d, body: %s", resp.StatusCode, string(body))
+		return nil, &models.HTTPStatusError{Code: resp.StatusCode, Body: string(body)}
 	}
 
 	var result models.RealTimeScraperMediaInfoResponse
@@ -302,8 +376,8 @@ func (r *Repository) GetInstagramReelInfo(reelURL string) (*models.RealTimeScrap
 	return &result, nil
 }
 
-func (r *Repository) GetTiktokVideoInfo(url string) (*models.TikTokVideoApiResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (r *Repository) GetTiktokVideoInfo(ctx context.Context, url string) (*models.TikTokVideoApiResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	if err := r.tiktokLimiter.Wait(ctx); err != nil {
 		return nil, err
@@ -343,7 +417,7 @@ func (r *Repository) GetTiktokVideoInfo(url string) (*models.TikTokVideoApiRespo
 	// Обрабатываем ответ
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("err http code: %d, body: %s", resp.StatusCode, string(body))
+		return nil, &models.HTTPStatusError{Code: resp.StatusCode, Body: string(body)}
 	}
 
 	var data models.TikTokVideoApiResponse
@@ -354,8 +428,8 @@ func (r *Repository) GetTiktokVideoInfo(url string) (*models.TikTokVideoApiRespo
 	return &data, nil
 }
 
-func (r *Repository) GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.TikTokVideo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (r *Repository) GetTiktokVideoByUserId(ctx context.Context, info *models.UrlInfo) ([]*models.TikTokVideo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	if r.rapidAPIKey == "" {
@@ -385,6 +459,9 @@ func (r *Repository) GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.Tik
 		q := req.URL.Query()
 		q.Add("user_id", info.URL)
 		q.Add("count", "30")
+		if cursor != "" {
+			q.Add("cursor", cursor)
+		}
 		req.URL.RawQuery = q.Encode()
 
 		// Устанавливаем заголовки
@@ -396,16 +473,20 @@ func (r *Repository) GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.Tik
 		if err != nil {
 			return nil, fmt.Errorf("failed to do request: %v", err)
 		}
-		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %v", err)
+		}
 
 		// Обрабатываем ответ
 		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
 			return nil, fmt.Errorf("err http code: %d, body: %s", resp.StatusCode, string(body))
 		}
 
 		var data models.TikTokPostsByUserResponse
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		if err := json.Unmarshal(body, &data); err != nil {
 			return nil, fmt.Errorf("tiktok failed to parse JSON: %v, url: %s", err, info.URL)
 		}
 
@@ -413,17 +494,24 @@ func (r *Repository) GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.Tik
 			break
 		}
 
+		var oldest time.Time
 		for _, video := range data.Data.Videos {
 			if len(videos) >= info.Count {
 				break
 			}
 
+			// закреплённые ролики идут первыми и бывают старыми, поэтому смотрим на конец страницы
+			oldest = utils.UnixTime(video.CreateTime)
+			if !info.Window.Contains(oldest) {
+				continue
+			}
+
 			videos = append(videos, &video)
 		}
 
 		// Обновляем курсор для следующего запроса
 		cursor = data.Data.Cursor
-		if cursor == "" {
+		if cursor == "" || info.Window.Passed(oldest) {
 			break
 		}
 	}
@@ -431,14 +519,35 @@ func (r *Repository) GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.Tik
 	return videos, nil
 }
 
-func (r *Repository) GetTiktokAccountIdByUsername(username string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (r *Repository) GetTiktokAccountIdByUsername(ctx context.Context, username string) (string, error) {
+	user, err := r.tiktokUser(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	return user.User.Id, nil
+}
+
+// GetTiktokAccount ID аккаунта и его профиль одним поиском: поиск платный,
+// повторять его ради профиля не нужно
+func (r *Repository) GetTiktokAccount(ctx context.Context, username string) (string, *models.AccountProfile, error) {
+	user, err := r.tiktokUser(ctx, username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return user.User.Id, user.ToProfile(), nil
+}
+
+// tiktokUser аккаунт из поиска по имени
+func (r *Repository) tiktokUser(ctx context.Context, username string) (*models.TikTokSearchUser, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 	if err := r.tiktokLimiter.Wait(ctx); err != nil {
-		return "", err
+		return nil, err
 	}
 	if r.rapidAPIKey == "" {
-		return "", fmt.Errorf("RAPIDAPI_KEY is not set")
+		return nil, fmt.Errorf("RAPIDAPI_KEY is not set")
 	}
 
 	// Создаем запрос с Query параметрами
@@ -449,7 +558,7 @@ func (r *Repository) GetTiktokAccountIdByUsername(username string) (string, erro
 		nil,
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	// Добавляем query параметры
@@ -465,28 +574,28 @@ func (r *Repository) GetTiktokAccountIdByUsername(username string) (string, erro
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to do request: %v", err)
+		return nil, fmt.Errorf("failed to do request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Обрабатываем ответ
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("err http code: %d, body: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("err http code: %d, body: %s", resp.StatusCode, string(body))
 	}
 
 	var data models.TikTokUserSearchResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", fmt.Errorf("failed to parse JSON: %v, url: %s", err, username)
+		return nil, fmt.Errorf("failed to parse JSON: %v, url: %s", err, username)
 	}
 
-	for _, account := range data.Data.UserList {
-		if account.User.Nickname == username {
-			return account.User.Id, nil
+	for i, account := range data.Data.UserList {
+		if account.User.Nickname == username || strings.EqualFold(account.User.UniqueID, username) {
+			return &data.Data.UserList[i], nil
 		}
 	}
 
-	return "", fmt.Errorf("failed to find account by username: %s", username)
+	return nil, fmt.Errorf("failed to find account by username: %s", username)
 }
 
 func getVkUserClipsEndpoint(identification, cursor string) string {
@@ -498,7 +607,8 @@ func getVkUserClipsEndpoint(identification, cursor string) string {
 	return endpoint
 }
 
-func getRapidRealTimeInstagramScraperUserReelsEndpoint(username, maxID string) string {
+// getRapidRealTimeInstagramScraperFeedEndpoint запрос страницы user_reels или user_posts
+func getRapidRealTimeInstagramScraperFeedEndpoint(feedURL, username, maxID string) string {
 	params := url.Values{}
 	params.Add("username_or_id", username)
 
@@ -506,5 +616,5 @@ func getRapidRealTimeInstagramScraperUserReelsEndpoint(username, maxID string) s
 		params.Add("max_id", maxID)
 	}
 
-	return fmt.Sprintf("%s?%s", constants.RapidRealTimeInstagramScraperUserReels, params.Encode())
+	return fmt.Sprintf("%s?%s", feedURL, params.Encode())
 }
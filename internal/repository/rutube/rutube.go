@@ -0,0 +1,145 @@
+package rutube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"inst_parser/internal/constants"
+	"inst_parser/internal/models"
+
+	"golang.org/x/time/rate"
+)
+
+// Client клиент публичного JSON API Rutube, ключ не нужен
+type Client struct {
+	logger           *slog.Logger
+	client           *http.Client
+	videoURL         string
+	channelVideosURL string
+	limiter          *rate.Limiter
+}
+
+func NewClient(logger *slog.Logger) *Client {
+	return &Client{
+		logger:           logger,
+		client:           &http.Client{Timeout: 30 * time.Second},
+		videoURL:         constants.RutubeVideo,
+		channelVideosURL: constants.RutubeChannelVideos,
+		limiter:          rate.NewLimiter(3, 3),
+	}
+}
+
+// Concurrency сколько запросов к Rutube имеет смысл выполнять одновременно
+func (c *Client) Concurrency() int {
+	return c.limiter.Burst()
+}
+
+// GetRutubeVideo ролик по ID из ссылки
+func (c *Client) GetRutubeVideo(ctx context.Context, videoID string) (*models.RutubeVideo, error) {
+	var video models.RutubeVideo
+	if err := c.get(ctx, c.videoURL+url.PathEscape(videoID)+"/", nil, &video); err != nil {
+		return nil, err
+	}
+
+	if video.ID == "" {
+		return nil, fmt.Errorf("%w: ролик %s", models.ErrContentNotFound, videoID)
+	}
+
+	return &video, nil
+}
+
+// GetRutubeChannelVideos последние info.Count роликов канала info.Identification, новые первыми
+func (c *Client) GetRutubeChannelVideos(ctx context.Context, info *models.AccountInfo) ([]*models.RutubeVideo, error) {
+	videos := make([]*models.RutubeVideo, 0, info.Count)
+	channelURL := c.channelVideosURL + url.PathEscape(info.Identification) + "/"
+
+	for page := 1; len(videos) < info.Count; page++ {
+		params := url.Values{}
+		params.Add("page", strconv.Itoa(page))
+
+		var resp models.RutubeVideosPage
+		if err := c.get(ctx, channelURL, params, &resp); err != nil {
+			// уже собранные ролики полезнее ошибки на середине канала
+			if len(videos) > 0 {
+				c.logger.Error("failed to fetch rutube channel page",
+					slog.String("channel_id", info.Identification),
+					slog.Int("page", page),
+					slog.String("err", err.Error()),
+				)
+				break
+			}
+			return nil, err
+		}
+
+		var oldest time.Time
+		for _, video := range resp.Results {
+			if len(videos) >= info.Count {
+				break
+			}
+
+			oldest = video.PublishedAt()
+			if !info.Window.Contains(oldest) {
+				continue
+			}
+			videos = append(videos, video)
+		}
+
+		if !resp.HasNext || len(resp.Results) == 0 || info.Window.Passed(oldest) {
+			break
+		}
+	}
+
+	return videos, nil
+}
+
+func (c *Client) get(ctx context.Context, requestURL string, params url.Values, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения ответа: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusUnauthorized:
+		// скрытые и доступные по ссылке с ключом ролики
+		return fmt.Errorf("%w: %s", models.ErrContentPrivate, string(body))
+	default:
+		return &models.HTTPStatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("ошибка парсинга JSON: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package rutube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inst_parser/internal/models"
+
+	"golang.org/x/time/rate"
+)
+
+const videoID = "0123456789abcdef0123456789abcdef"
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/video/" + videoID + "/":
+			fmt.Fprintf(w, `{"id":%q,"title":"Ролик","duration":125,"hits":1500,"comments_count":12,
+				"publication_ts":"2024-03-15T10:00:00","author":{"id":42,"name":"Автор","site_url":"https://rutube.ru/channel/42/"}}`, videoID)
+		case "/api/video/ffffffffffffffffffffffffffffffff/":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"detail":"private"}`)
+		case "/api/video/person/42/":
+			// по два ролика на странице, всего три
+			switch r.URL.Query().Get("page") {
+			case "1":
+				fmt.Fprint(w, `{"has_next":true,"page":1,"results":[{"id":"a3"},{"id":"a2"}]}`)
+			case "2":
+				fmt.Fprint(w, `{"has_next":false,"page":2,"results":[{"id":"a1"}]}`)
+			default:
+				t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.videoURL = srv.URL + "/api/video/"
+	c.channelVideosURL = srv.URL + "/api/video/person/"
+	c.limiter = rate.NewLimiter(rate.Inf, 3)
+
+	return c
+}
+
+func TestClient_GetRutubeVideo(t *testing.T) {
+	c := newTestClient(t)
+
+	tests := []struct {
+		name       string
+		videoID    string
+		wantStatus models.UrlStatus
+	}{
+		{name: "public video", videoID: videoID, wantStatus: models.UrlStatusOK},
+		{name: "private video", videoID: "ffffffffffffffffffffffffffffffff", wantStatus: models.UrlStatusPrivate},
+		{name: "deleted video", videoID: "00000000000000000000000000000000", wantStatus: models.UrlStatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetRutubeVideo(context.Background(), tt.videoID)
+			if status := models.ClassifyError(err); status != tt.wantStatus {
+				t.Fatalf("GetRutubeVideo() error = %v, status %v, want %v", err, status, tt.wantStatus)
+			}
+			if err != nil {
+				return
+			}
+
+			row := got.ToResultRow(got.URL())
+			if row.Views != 1500 || row.Comments != 12 || row.Duration != "2:05" ||
+				row.PublishDate != "2024-03-15 10:00:00" || row.OwnerUrl != "https://rutube.ru/channel/42/" {
+				t.Errorf("ToResultRow() = %+v", row)
+			}
+		})
+	}
+}
+
+func TestClient_GetRutubeChannelVideos(t *testing.T) {
+	c := newTestClient(t)
+
+	tests := []struct {
+		name    string
+		count   int
+		wantIDs []string
+	}{
+		{name: "first page", count: 1, wantIDs: []string{"a3"}},
+		{name: "all pages", count: 10, wantIDs: []string{"a3", "a2", "a1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos, err := c.GetRutubeChannelVideos(context.Background(), &models.AccountInfo{
+				Identification: "42",
+				Count:          tt.count,
+			})
+			if err != nil {
+				t.Fatalf("GetRutubeChannelVideos() error = %v", err)
+			}
+
+			if len(videos) != len(tt.wantIDs) {
+				t.Fatalf("GetRutubeChannelVideos() len = %d, want %d", len(videos), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if videos[i].ID != id {
+					t.Errorf("videos[%d].ID = %v, want %v", i, videos[i].ID, id)
+				}
+			}
+		})
+	}
+
+	if _, err := c.GetRutubeChannelVideos(context.Background(), &models.AccountInfo{Identification: "7", Count: 5}); !errors.As(err, new(*models.HTTPStatusError)) {
+		t.Errorf("GetRutubeChannelVideos() unknown channel error = %v, want HTTPStatusError", err)
+	}
+}
+
+func TestClient_GetRutubeChannelVideosWindow(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		switch page {
+		case "1":
+			fmt.Fprint(w, `{"has_next":true,"page":1,"results":[
+				{"id":"june","publication_ts":"2024-06-02T10:00:00"},
+				{"id":"may2","publication_ts":"2024-05-20T10:00:00"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"has_next":true,"page":2,"results":[
+				{"id":"may1","publication_ts":"2024-05-01T00:30:00"},
+				{"id":"april","publication_ts":"2024-04-28T10:00:00"}]}`)
+		default:
+			t.Errorf("page %s requested after the channel went past the window", page)
+			fmt.Fprint(w, `{"has_next":false,"results":[]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.channelVideosURL = srv.URL + "/"
+	c.limiter = rate.NewLimiter(rate.Inf, 3)
+
+	window, err := models.ParseDateWindow("2024-05-01", "2024-05-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	videos, err := c.GetRutubeChannelVideos(context.Background(), &models.AccountInfo{
+		Identification: "42",
+		Count:          100,
+		Window:         window,
+	})
+	if err != nil {
+		t.Fatalf("GetRutubeChannelVideos() error = %v", err)
+	}
+
+	var ids []string
+	for _, video := range videos {
+		ids = append(ids, video.ID)
+	}
+	if fmt.Sprint(ids) != "[may2 may1]" {
+		t.Errorf("GetRutubeChannelVideos() ids = %v, want [may2 may1]", ids)
+	}
+	if len(pages) != 2 {
+		t.Errorf("requested pages = %v, want 2 pages", pages)
+	}
+}
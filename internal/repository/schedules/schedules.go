@@ -0,0 +1,70 @@
+package schedules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"inst_parser/internal/models"
+)
+
+// Store хранит расписания в JSON-файле. Расписаний немного, поэтому файл
+// перезаписывается целиком при каждом изменении.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create schedules dir: %w", err)
+	}
+
+	return &Store{path: path}, nil
+}
+
+// Load читает расписания. Отсутствующий файл — пустой список.
+func (s *Store) Load() ([]*models.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedules: %w", err)
+	}
+
+	var schedules []*models.Schedule
+	if err = json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// Save атомарно заменяет файл переданными расписаниями
+func (s *Store) Save(schedules []*models.Schedule) error {
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedules: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpPath := s.path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace schedules: %w", err)
+	}
+
+	return nil
+}
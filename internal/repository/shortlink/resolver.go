@@ -0,0 +1,150 @@
+package shortlink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+// maxCacheSize при переполнении кэш сбрасывается целиком
+const maxCacheSize = 10000
+
+// userAgent сокращатели отдают редирект только браузерам
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+
+type cacheEntry struct {
+	url     string
+	expires time.Time
+}
+
+// Resolver раскрывает короткие ссылки, проходя редиректы вручную
+type Resolver struct {
+	client   *http.Client
+	timeout  time.Duration
+	maxHops  int
+	cacheTTL time.Duration
+	isShort  func(rawURL string) bool
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func NewResolver(timeout time.Duration, maxHops int, cacheTTL time.Duration) *Resolver {
+	return &Resolver{
+		client: &http.Client{
+			// редиректы проходим сами, чтобы остановиться на первой несокращённой ссылке
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		timeout:  timeout,
+		maxHops:  maxHops,
+		cacheTTL: cacheTTL,
+		isShort:  models.IsShortLink,
+		cache:    make(map[string]cacheEntry),
+	}
+}
+
+// Resolve возвращает ссылку, на которую ведёт короткая. Останавливается на первой
+// ссылке, не являющейся короткой, не более maxHops переходов и timeout на всё раскрытие.
+func (r *Resolver) Resolve(ctx context.Context, rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if cached, ok := r.cached(rawURL); ok {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	current := rawURL
+	if !strings.Contains(current, "://") {
+		current = "https://" + current
+	}
+
+	for hop := 0; hop < r.maxHops; hop++ {
+		next, err := r.follow(ctx, current)
+		if err != nil {
+			return "", err
+		}
+		if next == "" {
+			break
+		}
+
+		current = next
+		if !r.isShort(current) {
+			r.store(rawURL, current)
+			return current, nil
+		}
+	}
+
+	if r.isShort(current) {
+		return "", fmt.Errorf("short link %s was not resolved in %d hops", rawURL, r.maxHops)
+	}
+
+	r.store(rawURL, current)
+
+	return current, nil
+}
+
+// follow делает один запрос и возвращает адрес редиректа, пустой — редиректа нет
+func (r *Resolver) follow(ctx context.Context, current string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, current, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve short link: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", &models.HTTPStatusError{Code: resp.StatusCode}
+	}
+	if resp.StatusCode < http.StatusMultipleChoices {
+		return "", nil
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", nil
+	}
+
+	next, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect location %q: %w", location, err)
+	}
+
+	return resp.Request.URL.ResolveReference(next).String(), nil
+}
+
+func (r *Resolver) cached(rawURL string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[rawURL]
+	if !ok || time.Now().After(entry.expires) {
+		return "", false
+	}
+
+	return entry.url, true
+}
+
+func (r *Resolver) store(rawURL, resolved string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.cache) >= maxCacheSize {
+		r.cache = make(map[string]cacheEntry)
+	}
+
+	r.cache[rawURL] = cacheEntry{url: resolved, expires: time.Now().Add(r.cacheTTL)}
+}
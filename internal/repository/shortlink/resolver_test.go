@@ -0,0 +1,69 @@
+package shortlink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/short/a":
+			http.Redirect(w, r, "/short/b", http.StatusMovedPermanently)
+		case "/short/b":
+			http.Redirect(w, r, "/video/123?utm=1", http.StatusFound)
+		case "/short/loop":
+			http.Redirect(w, r, "/short/loop", http.StatusFound)
+		case "/short/gone":
+			http.NotFound(w, r)
+		default:
+			t.Errorf("resolver followed non-short link %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	type args struct {
+		url string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "two hops", args: args{url: srv.URL + "/short/a"}, want: srv.URL + "/video/123?utm=1"},
+		{name: "redirect loop", args: args{url: srv.URL + "/short/loop"}, wantErr: true},
+		{name: "not found", args: args{url: srv.URL + "/short/gone"}, wantErr: true},
+	}
+
+	r := NewResolver(time.Second, 3, time.Hour)
+	r.isShort = func(rawURL string) bool { return strings.Contains(rawURL, "/short/") }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Resolve(context.Background(), tt.args.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// повторное раскрытие берётся из кэша
+	before := requests.Load()
+	if _, err := r.Resolve(context.Background(), srv.URL+"/short/a"); err != nil {
+		t.Fatalf("Resolve() cached error = %v", err)
+	}
+	if requests.Load() != before {
+		t.Errorf("Resolve() made %d requests for cached link", requests.Load()-before)
+	}
+}
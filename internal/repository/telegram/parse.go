@@ -0,0 +1,191 @@
+package telegram
+
+import (
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"inst_parser/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// parsePosts разбирает посты страницы превью: блоки tgme_widget_message с data-post="channel/id"
+func parsePosts(r io.Reader) ([]*models.TelegramPost, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []*models.TelegramPost
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && hasClass(n, "tgme_widget_message") {
+			if post, ok := parsePost(n); ok {
+				posts = append(posts, post)
+			}
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return posts, nil
+}
+
+func parsePost(n *html.Node) (*models.TelegramPost, bool) {
+	channel, idValue, ok := strings.Cut(attr(n, "data-post"), "/")
+	if !ok {
+		return nil, false
+	}
+	id, err := strconv.Atoi(idValue)
+	if err != nil {
+		return nil, false
+	}
+
+	post := &models.TelegramPost{
+		Channel: channel,
+		ID:      id,
+	}
+
+	if text := findByClass(n, "tgme_widget_message_text"); text != nil {
+		post.Text = strings.TrimSpace(textContent(text))
+	}
+	if views := findByClass(n, "tgme_widget_message_views"); views != nil {
+		post.Views = parseCount(textContent(views))
+	}
+	if date := findByClass(n, "tgme_widget_message_date"); date != nil {
+		if t := findElement(date, "time"); t != nil {
+			post.PublishedAt, _ = time.Parse(time.RFC3339, attr(t, "datetime"))
+		}
+	}
+	if reactions := findByClass(n, "tgme_widget_message_reactions"); reactions != nil {
+		for reaction := range byClass(reactions, "tgme_reaction") {
+			post.Reactions += parseCount(ownText(reaction))
+		}
+	}
+
+	return post, true
+}
+
+// parseCount числа превью: 987, 4.8K, 1.2M
+func parseCount(value string) int64 {
+	value = strings.TrimSpace(strings.ReplaceAll(value, ",", "."))
+	if value == "" {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch value[len(value)-1] {
+	case 'K', 'k':
+		multiplier = 1e3
+	case 'M', 'm':
+		multiplier = 1e6
+	case 'B', 'b':
+		multiplier = 1e9
+	}
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int64(number*multiplier + 0.5)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	return n.Type == html.ElementNode && slices.Contains(strings.Fields(attr(n, "class")), class)
+}
+
+// byClass все потомки n с классом class, не заходя внутрь найденных
+func byClass(n *html.Node, class string) iter.Seq[*html.Node] {
+	return func(yield func(*html.Node) bool) {
+		var walk func(n *html.Node) bool
+		walk = func(n *html.Node) bool {
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				if hasClass(child, class) {
+					if !yield(child) {
+						return false
+					}
+					continue
+				}
+				if !walk(child) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(n)
+	}
+}
+
+func findByClass(n *html.Node, class string) *html.Node {
+	for found := range byClass(n, class) {
+		return found
+	}
+
+	return nil
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == tag {
+			return child
+		}
+		if found := findElement(child, tag); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// textContent текст узла, <br> становится переводом строки
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			sb.WriteString("\n")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return sb.String()
+}
+
+// ownText текст только прямых потомков: у реакции это счётчик без эмодзи
+func ownText(n *html.Node) string {
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			sb.WriteString(child.Data)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"inst_parser/internal/constants"
+	"inst_parser/internal/models"
+
+	"golang.org/x/time/rate"
+)
+
+// userAgent без браузерного User-Agent t.me отдаёт страницу-заглушку
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+
+// maxPages предел страниц превью на один канал: ~20 постов на странице
+const maxPages = 50
+
+// Client читает посты публичных каналов из веб-превью t.me/s/, без API и авторизации
+type Client struct {
+	logger  *slog.Logger
+	client  *http.Client
+	baseURL string
+	limiter *rate.Limiter
+}
+
+func NewClient(logger *slog.Logger) *Client {
+	return &Client{
+		logger: logger,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			// у канала без публичного превью t.me/s/ уводит на t.me/channel
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		baseURL: constants.TelegramWeb,
+		limiter: rate.NewLimiter(2, 2),
+	}
+}
+
+// Concurrency сколько страниц превью имеет смысл запрашивать одновременно
+func (c *Client) Concurrency() int {
+	return c.limiter.Burst()
+}
+
+// GetTelegramPost пост channel/postID. Превью отдаёт ленту до before, поэтому
+// запрашиваем страницу, которая заканчивается нужным постом.
+func (c *Client) GetTelegramPost(ctx context.Context, channel string, postID int) (*models.TelegramPost, error) {
+	posts, err := c.page(ctx, channel, postID+1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, post := range posts {
+		if post.ID == postID && strings.EqualFold(post.Channel, channel) {
+			return post, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: пост %s/%d", models.ErrContentNotFound, channel, postID)
+}
+
+// GetTelegramChannelPosts последние info.Count постов канала info.Identification, новые первыми
+func (c *Client) GetTelegramChannelPosts(ctx context.Context, info *models.AccountInfo) ([]*models.TelegramPost, error) {
+	posts := make([]*models.TelegramPost, 0, info.Count)
+	before := 0
+
+	for range maxPages {
+		page, err := c.page(ctx, info.Identification, before)
+		if err != nil {
+			// уже собранные посты полезнее ошибки на середине ленты
+			if len(posts) > 0 {
+				c.logger.Error("failed to fetch telegram page",
+					slog.String("channel", info.Identification),
+					slog.Int("before", before),
+					slog.String("err", err.Error()),
+				)
+				break
+			}
+			return nil, err
+		}
+
+		// страница идёт от старых к новым
+		added := false
+		var oldest time.Time
+		for i := len(page) - 1; i >= 0 && len(posts) < info.Count; i-- {
+			if before != 0 && page[i].ID >= before {
+				continue
+			}
+			before = page[i].ID
+			added = true
+			oldest = page[i].PublishedAt
+
+			if info.Window.Contains(page[i].PublishedAt) {
+				posts = append(posts, page[i])
+			}
+		}
+
+		if !added || len(posts) >= info.Count || before <= 1 || info.Window.Passed(oldest) {
+			break
+		}
+	}
+
+	return posts, nil
+}
+
+// page посты одной страницы превью; before = 0 — самые свежие
+func (c *Client) page(ctx context.Context, channel string, before int) ([]*models.TelegramPost, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf("%s/s/%s", c.baseURL, url.PathEscape(channel))
+	if before > 0 {
+		requestURL += "?before=" + strconv.Itoa(before)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return nil, fmt.Errorf("%w: у канала %s нет публичного превью", models.ErrContentNotFound, channel)
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &models.HTTPStatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	posts, err := parsePosts(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора страницы: %w", err)
+	}
+
+	slices.SortFunc(posts, func(a, b *models.TelegramPost) int {
+		return a.ID - b.ID
+	})
+
+	return posts, nil
+}
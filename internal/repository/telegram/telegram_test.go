@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+
+	"golang.org/x/time/rate"
+)
+
+// newFixtureServer отдаёт страницы превью из testdata: свежую ленту и ленту до поста 101
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/s/private":
+			http.Redirect(w, r, "/private", http.StatusFound)
+			return
+		case r.URL.Path != "/s/testchannel":
+			http.NotFound(w, r)
+			return
+		}
+
+		// как и t.me, отдаём страницу, на которой есть посты до before
+		switch r.URL.Query().Get("before") {
+		case "", "102", "103", "104":
+			http.ServeFile(w, r, "testdata/latest.html")
+		case "100", "101":
+			http.ServeFile(w, r, "testdata/before_101.html")
+		default:
+			w.Write([]byte("<html><body></body></html>"))
+		}
+	}))
+}
+
+func newTestClient(baseURL string) *Client {
+	c := NewClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	c.baseURL = baseURL
+	c.limiter = rate.NewLimiter(rate.Inf, 2)
+	return c
+}
+
+func TestClient_GetTelegramPost(t *testing.T) {
+	srv := newFixtureServer(t)
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	type args struct {
+		channel string
+		postID  int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *models.TelegramPost
+		wantErr error
+	}{
+		{
+			name: "post with reactions",
+			args: args{channel: "testchannel", postID: 103},
+			want: &models.TelegramPost{
+				Channel:     "testchannel",
+				ID:          103,
+				Text:        "Свежий пост",
+				Views:       10_500_000,
+				Reactions:   2_190_000,
+				PublishedAt: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "missing post",
+			args:    args{channel: "testchannel", postID: 150},
+			wantErr: models.ErrContentNotFound,
+		},
+		{
+			name:    "channel without preview",
+			args:    args{channel: "private", postID: 1},
+			wantErr: models.ErrContentNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetTelegramPost(context.Background(), tt.args.channel, tt.args.postID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTelegramPost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Channel != tt.want.Channel || got.ID != tt.want.ID || got.Text != tt.want.Text ||
+				got.Views != tt.want.Views || got.Reactions != tt.want.Reactions ||
+				!got.PublishedAt.Equal(tt.want.PublishedAt) {
+				t.Errorf("GetTelegramPost() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_GetTelegramChannelPosts(t *testing.T) {
+	srv := newFixtureServer(t)
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+
+	tests := []struct {
+		name    string
+		count   int
+		wantIDs []int
+	}{
+		{name: "one page", count: 2, wantIDs: []int{103, 102}},
+		{name: "two pages", count: 4, wantIDs: []int{103, 102, 101, 100}},
+		{name: "whole channel", count: 100, wantIDs: []int{103, 102, 101, 100, 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := c.GetTelegramChannelPosts(context.Background(), &models.AccountInfo{
+				Identification: "testchannel",
+				Count:          tt.count,
+			})
+			if err != nil {
+				t.Fatalf("GetTelegramChannelPosts() error = %v", err)
+			}
+
+			if len(posts) != len(tt.wantIDs) {
+				t.Fatalf("GetTelegramChannelPosts() len = %d, want %d", len(posts), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if posts[i].ID != id {
+					t.Errorf("posts[%d].ID = %d, want %d", i, posts[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func Test_parsePost(t *testing.T) {
+	srv := newFixtureServer(t)
+	defer srv.Close()
+
+	post, err := newTestClient(srv.URL).GetTelegramPost(context.Background(), "testchannel", 101)
+	if err != nil {
+		t.Fatalf("GetTelegramPost() error = %v", err)
+	}
+
+	if want := "Первый пост\nвторая строка ссылка"; post.Text != want {
+		t.Errorf("Text = %q, want %q", post.Text, want)
+	}
+	if post.Views != 4800 {
+		t.Errorf("Views = %d, want 4800", post.Views)
+	}
+	if post.Reactions != 1234 {
+		t.Errorf("Reactions = %d, want 1234", post.Reactions)
+	}
+}
+
+func Test_parseCount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{value: "987", want: 987},
+		{value: "4.8K", want: 4800},
+		{value: "1,5K", want: 1500},
+		{value: "2.19M", want: 2_190_000},
+		{value: " 12 ", want: 12},
+		{value: "", want: 0},
+		{value: "n/a", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseCount(tt.value); got != tt.want {
+				t.Errorf("parseCount(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
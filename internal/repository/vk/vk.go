@@ -2,6 +2,7 @@ package vk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -33,8 +34,13 @@ func NewRepository(logger *slog.Logger, accessToken string) *Repository {
 	}
 }
 
-func (r *Repository) GroupID(groupName string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// Concurrency сколько запросов к VK API имеет смысл выполнять одновременно
+func (r *Repository) Concurrency() int {
+	return r.limiter.Burst()
+}
+
+func (r *Repository) GroupID(ctx context.Context, groupName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if err := r.limiter.Wait(ctx); err != nil {
@@ -50,7 +56,7 @@ func (r *Repository) GroupID(groupName string) (string, error) {
 
 	params := api.Params{
 		"group_id": groupName,
-	}
+	}.WithContext(ctx)
 
 	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &groupInfo, params); err != nil {
 		return "", fmt.Errorf("failed to get group info: group_id = %s, err = %w", groupName, err)
@@ -63,8 +69,8 @@ func (r *Repository) GroupID(groupName string) (string, error) {
 	return strconv.Itoa(-groupInfo.Groups[0].ID), nil // Для групп ID отрицательный
 }
 
-func (r *Repository) UserID(userName string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (r *Repository) UserID(ctx context.Context, userName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if err := r.limiter.Wait(ctx); err != nil {
@@ -78,7 +84,7 @@ func (r *Repository) UserID(userName string) (string, error) {
 
 	params := api.Params{
 		"user_ids": userName,
-	}
+	}.WithContext(ctx)
 
 	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &userInfo, params); err != nil {
 		return "", fmt.Errorf("failed to get user info: user_id = %s, err = %w", userName, err)
@@ -91,8 +97,105 @@ func (r *Repository) UserID(userName string) (string, error) {
 	return strconv.Itoa(userInfo[0].ID), nil
 }
 
-func (r *Repository) PostInfo(postID string) (*models.VKClipInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GroupProfile профиль сообщества из groups.getById
+func (r *Repository) GroupProfile(ctx context.Context, groupName string) (*models.AccountProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	const vkApiMethod = "groups.getById"
+	var groupInfo struct {
+		Groups []struct {
+			Name         string             `json:"name"`
+			MembersCount int64              `json:"members_count"`
+			Verified     object.BaseBoolInt `json:"verified"`
+			Photo200     string             `json:"photo_200"`
+			Counters     struct {
+				Videos int64 `json:"videos"`
+				Clips  int64 `json:"clips"`
+			} `json:"counters"`
+		} `json:"groups"`
+	}
+
+	params := api.Params{
+		"group_id": groupName,
+		"fields":   "members_count,verified,photo_200,counters",
+	}.WithContext(ctx)
+
+	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &groupInfo, params); err != nil {
+		return nil, fmt.Errorf("failed to get group info: group_id = %s, err = %w", groupName, err)
+	}
+
+	if len(groupInfo.Groups) == 0 {
+		return nil, fmt.Errorf("%w: group_id = %s", models.ErrContentNotFound, groupName)
+	}
+
+	group := groupInfo.Groups[0]
+
+	return &models.AccountProfile{
+		Platform:    models.VKGroupParsingType,
+		DisplayName: group.Name,
+		Followers:   group.MembersCount,
+		MediaCount:  max(group.Counters.Clips, group.Counters.Videos),
+		Verified:    bool(group.Verified),
+		AvatarURL:   group.Photo200,
+	}, nil
+}
+
+// UserProfile профиль пользователя из users.get
+func (r *Repository) UserProfile(ctx context.Context, userName string) (*models.AccountProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := r.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	const vkApiMethod = "users.get"
+	var userInfo []struct {
+		FirstName      string             `json:"first_name"`
+		LastName       string             `json:"last_name"`
+		FollowersCount int64              `json:"followers_count"`
+		Verified       object.BaseBoolInt `json:"verified"`
+		Photo200       string             `json:"photo_200"`
+		Counters       struct {
+			Videos        int64 `json:"videos"`
+			Clips         int64 `json:"clips"`
+			Subscriptions int64 `json:"subscriptions"`
+		} `json:"counters"`
+	}
+
+	params := api.Params{
+		"user_ids": userName,
+		"fields":   "followers_count,verified,photo_200,counters",
+	}.WithContext(ctx)
+
+	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &userInfo, params); err != nil {
+		return nil, fmt.Errorf("failed to get user info: user_id = %s, err = %w", userName, err)
+	}
+
+	if len(userInfo) == 0 {
+		return nil, fmt.Errorf("%w: user_id = %s", models.ErrContentNotFound, userName)
+	}
+
+	user := userInfo[0]
+
+	return &models.AccountProfile{
+		Platform:    models.VKGroupParsingType,
+		DisplayName: strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Followers:   user.FollowersCount,
+		Following:   user.Counters.Subscriptions,
+		MediaCount:  max(user.Counters.Clips, user.Counters.Videos),
+		Verified:    bool(user.Verified),
+		AvatarURL:   user.Photo200,
+	}, nil
+}
+
+func (r *Repository) PostInfo(ctx context.Context, postID string) (*models.VKClipInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if err := r.limiter.Wait(ctx); err != nil {
@@ -103,17 +206,16 @@ func (r *Repository) PostInfo(postID string) (*models.VKClipInfo, error) {
 
 	params := api.Params{
 		"posts": postID,
-	}
+	}.WithContext(ctx)
 
 	var response models.WallGetByIDResponse
 
 	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &response, params); err != nil {
-		fmt.Errorf("failed to get post info: post_id = %s, err = %w", postID, err)
-		return nil, err
+		return nil, fmt.Errorf("failed to get post info: post_id = %s, err = %w", postID, wrapAPIError(err))
 	}
 
 	if len(response.Items) == 0 {
-		return nil, fmt.Errorf("post not found")
+		return nil, fmt.Errorf("%w: post", models.ErrContentNotFound)
 	}
 
 	var (
@@ -139,7 +241,7 @@ func (r *Repository) PostInfo(postID string) (*models.VKClipInfo, error) {
 			comments = response.Items[0].Comments.Count
 		}
 
-		adsInfo, err := r.getAdvertiserInfo(response.Items[0].AuthorAd.AdvertiserInfoUrl)
+		adsInfo, err := r.getAdvertiserInfo(ctx, response.Items[0].AuthorAd.AdvertiserInfoUrl)
 		if err != nil {
 			r.logger.Warn("failed to get advertiser info",
 				slog.String("err", err.Error()),
@@ -163,7 +265,7 @@ func (r *Repository) PostInfo(postID string) (*models.VKClipInfo, error) {
 		}, nil
 	}
 
-	adsInfo, err := r.getAdvertiserInfo(response.Items[0].AuthorAd.AdvertiserInfoUrl)
+	adsInfo, err := r.getAdvertiserInfo(ctx, response.Items[0].AuthorAd.AdvertiserInfoUrl)
 	if err != nil {
 		r.logger.Warn("failed to get advertiser info",
 			slog.String("err", err.Error()),
@@ -189,8 +291,9 @@ func (r *Repository) PostInfo(postID string) (*models.VKClipInfo, error) {
 	return postInfo, nil
 }
 
-func (r *Repository) ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// ClipInfo клип или видео, video.get отдаёт их одинаково: статистика, ОРД и ссылки на файлы
+func (r *Repository) ClipInfo(ctx context.Context, ownerID, clipID int) (*models.VKClipInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if err := r.limiter.Wait(ctx); err != nil {
@@ -201,15 +304,15 @@ func (r *Repository) ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error) {
 	params := api.Params{
 		"videos":   fmt.Sprintf("%d_%d", ownerID, clipID),
 		"extended": 1,
-	}
+	}.WithContext(ctx)
 
 	var response models.VideoGetResponse
 	if err := r.vkApi.RequestUnmarshal(vkApiMethod, &response, params); err != nil {
-		return nil, fmt.Errorf("failed to get clip info: %w", err)
+		return nil, fmt.Errorf("failed to get clip info: %w", wrapAPIError(err))
 	}
 
 	if response.Count == 0 {
-		return nil, fmt.Errorf("clip not found")
+		return nil, fmt.Errorf("%w: clip", models.ErrContentNotFound)
 	}
 
 	item := response.Items[0]
@@ -219,7 +322,7 @@ func (r *Repository) ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error) {
 		advertiser = item.OrdInfo.Advertisers[0]
 	}
 
-	adsInfo, err := r.getAdvertiserInfo(advertiser.Url)
+	adsInfo, err := r.getAdvertiserInfo(ctx, advertiser.Url)
 	if err != nil {
 		r.logger.Warn("failed to get advertiser info",
 			slog.String("err", err.Error()),
@@ -247,8 +350,8 @@ func (r *Repository) ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error) {
 	return clipInfo, nil
 }
 
-func (r *Repository) getAdvertiserInfo(eridURL string) (models.AdvertiserInfoFromUrl, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (r *Repository) getAdvertiserInfo(ctx context.Context, eridURL string) (models.AdvertiserInfoFromUrl, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	if err := r.limiter.Wait(ctx); err != nil {
@@ -263,7 +366,7 @@ func (r *Repository) getAdvertiserInfo(eridURL string) (models.AdvertiserInfoFro
 		Timeout: 10 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", eridURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, eridURL, nil)
 	if err != nil {
 		return models.AdvertiserInfoFromUrl{}, fmt.Errorf("ошибка создания запроса: %w", err)
 	}
@@ -373,3 +476,20 @@ func findHighQualityLink(item object.VideoVideoFiles) string {
 
 	return ""
 }
+
+// wrapAPIError помечает ошибку VK API причиной, по которой определяется статус ссылки
+func wrapAPIError(err error) error {
+	switch {
+	case errors.Is(err, api.ErrAccess),
+		errors.Is(err, api.ErrPrivateProfile),
+		errors.Is(err, api.ErrAccessVideo),
+		errors.Is(err, api.ErrWallAccessPost):
+		return fmt.Errorf("%w: %w", models.ErrContentPrivate, err)
+	case errors.Is(err, api.ErrNotFound), errors.Is(err, api.ErrBlocked):
+		return fmt.Errorf("%w: %w", models.ErrContentNotFound, err)
+	case errors.Is(err, api.ErrTooMany), errors.Is(err, api.ErrRateLimit):
+		return fmt.Errorf("%w: %w", models.ErrRateLimited, err)
+	default:
+		return err
+	}
+}
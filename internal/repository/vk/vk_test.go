@@ -1,6 +1,7 @@
 package vk
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -29,7 +30,7 @@ func TestRepository_getAdvertiserInfo(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Repository{}
-			got, err := r.getAdvertiserInfo(tt.args.eridURL)
+			got, err := r.getAdvertiserInfo(context.Background(), tt.args.eridURL)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getAdvertiserInfo() error = %v, wantErr %v", err, tt.wantErr)
 				return
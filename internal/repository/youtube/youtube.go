@@ -3,16 +3,23 @@ package youtube
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"inst_parser/internal/constants"
 	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
 
 	"golang.org/x/time/rate"
 )
@@ -22,19 +29,85 @@ type Client struct {
 	logger  *slog.Logger
 	apiKey  string
 	limiter *rate.Limiter
+	// shortsClient не следует редиректам: /shorts/ID у обычного видео уводит на /watch
+	shortsClient *http.Client
+	// shortsLimiter страницы шортсов — сайт youtube.com, а не API, у них своё ограничение
+	shortsLimiter *rate.Limiter
+	shortsPage    string
+	videosURL     string
 }
 
+const (
+	// maxShortDuration шортсы не длиннее трёх минут, более длинные ролики не проверяем
+	maxShortDuration = 3 * time.Minute
+	// maxShortsChecks сколько страниц шортсов проверяем на пачку роликов API (до 50),
+	// остальные короткие ролики определяем по длительности
+	maxShortsChecks = 20
+)
+
 func NewYouTubeClient(logger *slog.Logger, apiKey string) *Client {
 	return &Client{
 		apiKey:  apiKey,
 		logger:  logger,
 		limiter: rate.NewLimiter(2, 2),
+		shortsClient: &http.Client{
+			Timeout: 10 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		shortsLimiter: rate.NewLimiter(5, 4),
+		shortsPage:    constants.YoutubeShortsPage,
+		videosURL:     constants.YoutubeVideos,
 	}
 }
 
+// Concurrency сколько запросов к YouTube API имеет смысл выполнять одновременно
+func (c *Client) Concurrency() int {
+	return c.limiter.Burst()
+}
+
 // YoutubeShortInfo получает статистику для YouTube видео или Shorts
-func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+func (c *Client) YoutubeShortInfo(ctx context.Context, videoID string) (*models.YoutubeShortInfoApiResponse, error) {
+	infos, err := c.YoutubeVideosInfo(ctx, []string{videoID})
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := infos[videoID]
+	if !ok {
+		return nil, fmt.Errorf("%w: видео с ID %s", models.ErrContentNotFound, videoID)
+	}
+
+	return info, nil
+}
+
+// YoutubeVideosInfo статистика роликов пачками по maxVideosPerRequest ID за запрос:
+// один запрос стоит одну единицу квоты независимо от числа ID. Удалённых и
+// приватных роликов в ответе нет. При ошибке возвращает уже полученные пачки и ошибку.
+func (c *Client) YoutubeVideosInfo(ctx context.Context, videoIDs []string) (map[string]*models.YoutubeShortInfoApiResponse, error) {
+	infos := make(map[string]*models.YoutubeShortInfoApiResponse, len(videoIDs))
+
+	for chunk := range slices.Chunk(videoIDs, maxVideosPerRequest) {
+		items, err := c.videos(ctx, chunk)
+		if err != nil {
+			return infos, err
+		}
+
+		for _, info := range c.videoInfos(ctx, items) {
+			infos[info.ID] = info
+		}
+	}
+
+	return infos, nil
+}
+
+// maxVideosPerRequest предел id в одном запросе videos.list
+const maxVideosPerRequest = 50
+
+// videos один запрос videos.list, не больше maxVideosPerRequest ID
+func (c *Client) videos(ctx context.Context, videoIDs []string) ([]models.VideoItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	if err := c.limiter.Wait(ctx); err != nil {
@@ -43,11 +116,12 @@ func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiRe
 
 	// Формируем URL запроса
 	params := url.Values{}
-	params.Add("part", "snippet,statistics")
-	params.Add("id", videoID)
+	params.Add("part", "snippet,statistics,contentDetails,liveStreamingDetails")
+	params.Add("id", strings.Join(videoIDs, ","))
+	params.Add("maxResults", strconv.Itoa(maxVideosPerRequest))
 	params.Add("key", c.apiKey)
 
-	requestURL := fmt.Sprintf("%s?%s", constants.YoutubeVideos, params.Encode())
+	requestURL := fmt.Sprintf("%s?%s", c.videosURL, params.Encode())
 
 	// Создаём HTTP запрос с контекстом
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
@@ -56,8 +130,7 @@ func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiRe
 	}
 
 	// Выполняем запрос
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
 	}
@@ -66,7 +139,7 @@ func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiRe
 	// Проверяем статус ответа
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API вернул статус %d: %s", resp.StatusCode, string(body))
+		return nil, &models.HTTPStatusError{Code: resp.StatusCode, Body: string(body)}
 	}
 
 	// Читаем тело ответа
@@ -81,13 +154,49 @@ func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiRe
 		return nil, fmt.Errorf("ошибка парсинга JSON: %w", err)
 	}
 
-	// Проверяем, что есть результаты
-	if len(apiResponse.Items) == 0 {
-		return nil, fmt.Errorf("видео с ID %s не найдено", videoID)
+	return apiResponse.Items, nil
+}
+
+// videoInfos собирает ответы по роликам пачки. Проверка страниц шортсов идёт
+// параллельно, иначе на пачке коротких роликов она дольше самого запроса к API,
+// но не чаще shortsLimiter и не больше maxShortsChecks страниц на пачку.
+func (c *Client) videoInfos(ctx context.Context, items []models.VideoItem) []*models.YoutubeShortInfoApiResponse {
+	infos := make([]*models.YoutubeShortInfoApiResponse, len(items))
+	sem := make(chan struct{}, c.shortsLimiter.Burst())
+
+	var checks atomic.Int32
+	checks.Store(maxShortsChecks)
+
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			infos[i] = c.videoInfo(ctx, items[i], &checks)
+		}()
 	}
+	wg.Wait()
 
-	item := apiResponse.Items[0]
-	likes, _ := strconv.Atoi(apiResponse.Items[0].Statistics.LikeCount)
+	return infos
+}
+
+// videoInfo собирает ответ по ролику и определяет его вид, checks — оставшиеся проверки страниц шортсов
+func (c *Client) videoInfo(ctx context.Context, item models.VideoItem, checks *atomic.Int32) *models.YoutubeShortInfoApiResponse {
+	likes, _ := strconv.Atoi(item.Statistics.LikeCount)
+
+	var duration time.Duration
+	if item.ContentDetails.Duration != "" {
+		var err error
+		if duration, err = utils.ParseISODuration(item.ContentDetails.Duration); err != nil {
+			c.logger.Warn("failed to parse youtube duration",
+				slog.String("video_id", item.ID),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
 
 	return &models.YoutubeShortInfoApiResponse{
 		AccountURL:    fmt.Sprintf("https://www.youtube.com/channel/%s", item.Snippet.ChannelId),
@@ -98,59 +207,161 @@ func (c *Client) YoutubeShortInfo(videoID string) (*models.YoutubeShortInfoApiRe
 		Description:   item.Snippet.Description,
 		PublishedDate: item.Snippet.PublishedAt,
 		CommentCount:  item.Statistics.CommentCount,
-	}, nil
+		ContentType:   c.contentType(ctx, item, duration, checks),
+		Duration:      duration,
+	}
+}
+
+// contentType API не отличает шортс от видео, поэтому короткие ролики
+// проверяем по странице /shorts/ID: у шортса она открывается без редиректа.
+// Когда проверки пачки исчерпаны или страница не ответила, шортсом считаем
+// всё, что не длиннее минуты.
+func (c *Client) contentType(ctx context.Context, item models.VideoItem, duration time.Duration, checks *atomic.Int32) models.YoutubeContentType {
+	if item.IsLive() {
+		return models.YoutubeContentLive
+	}
+	if duration <= 0 || duration > maxShortDuration {
+		return models.YoutubeContentVideo
+	}
+
+	isShort := duration <= time.Minute
+	if checks.Add(-1) >= 0 {
+		checked, err := c.isShort(ctx, item.ID)
+		if err != nil {
+			c.logger.Warn("failed to check youtube shorts page",
+				slog.String("video_id", item.ID),
+				slog.String("err", err.Error()),
+			)
+		} else {
+			isShort = checked
+		}
+	}
+
+	if isShort {
+		return models.YoutubeContentShort
+	}
+
+	return models.YoutubeContentVideo
+}
+
+func (c *Client) isShort(ctx context.Context, videoID string) (bool, error) {
+	if err := c.shortsLimiter.Wait(ctx); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.shortsPage+videoID, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.shortsClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return true, nil
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+// GetChannelIDByUsername плейлист загрузок канала. username — @handle, ID канала UC…
+// или имя из старых ссылок /c/ и /user/, которое API ищет по forUsername, а при неудаче по handle
+func (c *Client) GetChannelIDByUsername(ctx context.Context, username string) (string, error) {
+	channel, err := c.findChannel(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	return channel.ContentDetails.RelatedPlaylists.Uploads, nil
 }
 
-func (c *Client) GetChannelIDByUsername(username string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+// GetChannel плейлист загрузок и профиль канала одним поиском: подписчики,
+// число роликов, название и аватар приходят в том же ответе channels.list
+func (c *Client) GetChannel(ctx context.Context, username string) (string, *models.AccountProfile, error) {
+	channel, err := c.findChannel(ctx, username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return channel.ContentDetails.RelatedPlaylists.Uploads, channel.ToProfile(), nil
+}
+
+func (c *Client) findChannel(ctx context.Context, username string) (*models.YouTubeChannel, error) {
+	switch {
+	case strings.HasPrefix(username, "@"):
+		return c.channel(ctx, "forHandle", username)
+	case channelIDRe.MatchString(username):
+		return c.channel(ctx, "id", username)
+	}
+
+	channel, err := c.channel(ctx, "forUsername", username)
+	if errors.Is(err, models.ErrContentNotFound) {
+		return c.channel(ctx, "forHandle", "@"+username)
+	}
+
+	return channel, err
+}
+
+// channelIDRe ID канала: UC и 22 символа
+var channelIDRe = regexp.MustCompile(`^UC[\w-]{22}$`)
+
+// channel один запрос channels.list: части ответа на квоту не влияют, поэтому берём сразу все нужные
+func (c *Client) channel(ctx context.Context, filter, value string) (*models.YouTubeChannel, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
 
 	if err := c.limiter.Wait(ctx); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	params := url.Values{}
-	params.Add("part", "contentDetails")
-	params.Add("forHandle", username)
+	params.Add("part", "snippet,statistics,contentDetails")
+	params.Add(filter, value)
 	params.Add("key", c.apiKey)
 
 	requestURL := fmt.Sprintf("%s?%s", constants.YoutubeChannels, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API вернул статус %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API вернул статус %d: %s", resp.StatusCode, string(body))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var channelResp models.ChannelResponse
 	if err := json.Unmarshal(body, &channelResp); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(channelResp.Items) == 0 {
-		return "", fmt.Errorf("канал не найден")
+		return nil, fmt.Errorf("%w: канал %s", models.ErrContentNotFound, value)
 	}
 
-	return channelResp.Items[0].ContentDetails.RelatedPlaylists.Uploads, nil
+	return &channelResp.Items[0], nil
 }
 
 // GetShortsInfoByAccountName получает все видео из плейлиста
-func (c *Client) GetShortsInfoByAccountName(accountInfo *models.AccountInfo) ([]*models.YoutubeShortInfoApiResponse, error) {
+func (c *Client) GetShortsInfoByAccountName(ctx context.Context, accountInfo *models.AccountInfo) ([]*models.YoutubeShortInfoApiResponse, error) {
 	pageToken := ""
 	shortsInfo := make([]*models.YoutubeShortInfoApiResponse, 0, accountInfo.Count)
 
@@ -166,7 +377,7 @@ func (c *Client) GetShortsInfoByAccountName(accountInfo *models.AccountInfo) ([]
 		}
 
 		requestURL := fmt.Sprintf("%s?%s", constants.YoutubePlaylistItems, params.Encode())
-		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 		defer cancel()
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
@@ -200,28 +411,40 @@ func (c *Client) GetShortsInfoByAccountName(accountInfo *models.AccountInfo) ([]
 			break
 		}
 
+		videoIDs := make([]string, 0, len(playlistResp.Items))
+		var oldest time.Time
 		for _, item := range playlistResp.Items {
-			if len(shortsInfo) >= accountInfo.Count {
+			if len(shortsInfo)+len(videoIDs) >= accountInfo.Count {
 				break
 			}
 
-			result, err := c.YoutubeShortInfo(item.Snippet.ResourceID.VideoID)
-			if err != nil {
-				c.logger.Error("failed to fetch shorts: %w, shortID - %s", err, item.Snippet.ResourceID.VideoID)
-				shortsInfo = append(
-					shortsInfo,
-					&models.YoutubeShortInfoApiResponse{
-						ID: item.Snippet.ResourceID.VideoID,
-					},
-				)
+			// плейлист загрузок идёт от новых к старым
+			oldest, _ = time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+			if !accountInfo.Window.Contains(oldest) {
 				continue
 			}
+			videoIDs = append(videoIDs, item.Snippet.ResourceID.VideoID)
+		}
 
-			shortsInfo = append(shortsInfo, result)
+		// вся страница плейлиста одним запросом videos.list
+		infos, err := c.YoutubeVideosInfo(ctx, videoIDs)
+		if err != nil {
+			c.logger.Error("failed to fetch youtube videos",
+				slog.String("playlist_id", accountInfo.Identification),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		for _, videoID := range videoIDs {
+			info, ok := infos[videoID]
+			if !ok {
+				info = &models.YoutubeShortInfoApiResponse{ID: videoID}
+			}
+			shortsInfo = append(shortsInfo, info)
 		}
 
 		pageToken = playlistResp.NextPageToken
-		if pageToken == "" {
+		if pageToken == "" || accountInfo.Window.Passed(oldest) {
 			break
 		}
 	}
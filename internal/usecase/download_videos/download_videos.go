@@ -2,6 +2,7 @@ package download_videos
 
 import (
 	"archive/zip"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -19,11 +20,11 @@ type (
 	}
 
 	VkClipInfoProvider interface {
-		ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error)
+		ClipInfo(ctx context.Context, ownerID, clipID int) (*models.VKClipInfo, error)
 	}
 
 	InstagramReelInfoProvider interface {
-		GetInstagramReelInfo(reelURL string) (*models.RealTimeScraperMediaInfoResponse, error)
+		GetInstagramReelInfo(ctx context.Context, reelURL string) (*models.RealTimeScraperMediaInfoResponse, error)
 	}
 )
 
@@ -49,11 +50,11 @@ func NewUsecase(
 }
 
 type result struct {
-	path string
-	err  error
+	paths []string
+	err   error
 }
 
-func (u *Usecase) DownloadVideos(urls []string) ([]byte, []string, error) {
+func (u *Usecase) DownloadVideos(ctx context.Context, urls []string) ([]byte, []string, error) {
 	// step 1. check type of urls
 	// Создаём временную директорию
 	tmpDir, err := os.MkdirTemp("", "videos_*")
@@ -83,9 +84,9 @@ func (u *Usecase) DownloadVideos(urls []string) ([]byte, []string, error) {
 		go func(i int, url string) {
 			defer wg.Done()
 
-			path, err := u.processOneUrl(url, tmpDir, parsingType)
+			paths, err := u.processOneUrl(ctx, url, tmpDir, parsingType)
 
-			results[i] = result{path, err}
+			results[i] = result{paths, err}
 		}(i, url)
 	}
 
@@ -95,7 +96,7 @@ func (u *Usecase) DownloadVideos(urls []string) ([]byte, []string, error) {
 		if res.err != nil {
 			errors = append(errors, fmt.Sprintf("URL[%d]: %v", i, res.err))
 		} else {
-			downloadedFiles = append(downloadedFiles, res.path)
+			downloadedFiles = append(downloadedFiles, res.paths...)
 		}
 	}
 
@@ -117,40 +118,34 @@ func (u *Usecase) DownloadVideos(urls []string) ([]byte, []string, error) {
 	return zipBytes, errors, nil
 }
 
-func (u *Usecase) processOneUrl(url, dir string, parsingType models.ParsingType) (string, error) {
-	var (
-		path string
-		err  error
-	)
-
+// processOneUrl скачивает файлы по ссылке: ролик VK или публикацию Instagram, у карусели — все элементы
+func (u *Usecase) processOneUrl(ctx context.Context, url, dir string, parsingType models.ParsingType) ([]string, error) {
 	switch parsingType {
 	case models.VKGroupParsingType:
-		path, err = u.processVkVideo(url, dir)
+		path, err := u.processVkVideo(ctx, url, dir)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
+		return []string{path}, nil
 	case models.InstagramParsingType:
-		path, err = u.processInstagramVideo(url, dir)
-		if err != nil {
-			return "", err
-		}
+		return u.processInstagramMedia(ctx, url, dir)
 	}
 
-	return path, nil
+	return nil, nil
 }
 
-func (u *Usecase) processVkVideo(url, dir string) (string, error) {
-	ownerID, clipID, err := models.ParseVkClipURL(url)
+func (u *Usecase) processVkVideo(ctx context.Context, url, dir string) (string, error) {
+	ownerID, clipID, err := models.ParseVkVideoURL(url)
 	if err != nil {
-		u.logger.Error("Error parsing vk clip url",
+		u.logger.Error("Error parsing vk video url",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return "", fmt.Errorf("error parsing vk clip url, err: %v", err)
+		return "", fmt.Errorf("error parsing vk video url, err: %v", err)
 	}
 
-	clipInfo, err := u.vkClipInfoProvider.ClipInfo(ownerID, clipID)
+	clipInfo, err := u.vkClipInfoProvider.ClipInfo(ctx, ownerID, clipID)
 	if err != nil {
 		u.logger.Error("Error getting clip info",
 			slog.String("url", url),
@@ -173,28 +168,78 @@ func (u *Usecase) processVkVideo(url, dir string) (string, error) {
 	return path, nil
 }
 
-func (u *Usecase) processInstagramVideo(url, dir string) (string, error) {
-	apiResp, err := u.instagramReelInfoProvider.GetInstagramReelInfo(url)
+func (u *Usecase) processInstagramMedia(ctx context.Context, url, dir string) ([]string, error) {
+	apiResp, err := u.instagramReelInfoProvider.GetInstagramReelInfo(ctx, url)
 	if err != nil {
 		u.logger.Error("Error getting instagram reel info",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
-		return "", err
+		return nil, err
 	}
 
 	resultRow, err := models.ProcessInstagramResponse(apiResp, url, true)
+	if err != nil {
+		u.logger.Error("Error processing instagram response",
+			slog.String("url", url),
+			slog.String("err", err.Error()),
+		)
+		return nil, err
+	}
+
+	switch models.InstagramMediaType(resultRow.ContentType) {
+	case models.InstagramMediaPhoto, models.InstagramMediaCarousel:
+		return u.downloadInstagramFiles(url, resultRow.MediaUrls, dir)
+	}
+
+	path, err := u.processInstagramVideo(url, resultRow, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{path}, nil
+}
+
+// downloadInstagramFiles скачивает все фото и видео публикации; ошибка, только если не скачалось ничего
+func (u *Usecase) downloadInstagramFiles(url string, mediaUrls []string, dir string) ([]string, error) {
+	if len(mediaUrls) == 0 {
+		return nil, fmt.Errorf("error getting instagram media urls: %s", url)
+	}
 
+	paths := make([]string, 0, len(mediaUrls))
+	for i, mediaUrl := range mediaUrls {
+		path, err := u.videoDownloader.DownloadVideo(fmt.Sprintf("%s_%d", url, i+1), mediaUrl, dir)
+		if err != nil {
+			u.logger.Error("Error downloading instagram media",
+				slog.String("url", url),
+				slog.String("err", err.Error()),
+			)
+
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("failed to download instagram media: %s", url)
+	}
+
+	return paths, nil
+}
+
+func (u *Usecase) processInstagramVideo(url string, resultRow *models.ResultRowUrl, dir string) (string, error) {
 	if resultRow.VideoUrls == nil {
-		// find video ur
 		u.logger.Error("Error getting instagram reel video urls",
 			slog.String("url", url),
-			slog.String("err", err.Error()),
 		)
 		return "", fmt.Errorf("error getting instagram video urls: %v", resultRow.VideoUrls)
 	}
 
-	var path string
+	var (
+		path string
+		err  error
+	)
 	for _, item := range resultRow.VideoUrls {
 		path, err = u.videoDownloader.DownloadVideo(
 			resultRow.URL,
@@ -215,6 +260,10 @@ func (u *Usecase) processInstagramVideo(url, dir string) (string, error) {
 		}
 	}
 
+	if path == "" {
+		return "", fmt.Errorf("failed to download instagram video: %s", url)
+	}
+
 	return path, nil
 }
 
@@ -1,11 +1,15 @@
 package parsing_account
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"inst_parser/internal/constants"
 	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
 	"log/slog"
 	"strconv"
+	"time"
 )
 
 type Usecase struct {
@@ -13,11 +17,15 @@ type Usecase struct {
 	accountUrlsProvider             AccountUrlsProvider
 	vkGroupIDProvider               VKGroupIDProvider
 	trackerService                  TrackerService
+	jobTracker                      JobTracker
 	vkClipInfoProvider              VKClipInfoProvider
 	dataInserter                    DataInserter
 	instagramGetReelsInfoForAccount InstagramGetReelsInfoForAccount
 	youtubeChannelShortsData        YoutubeChannelShortsData
 	tiktokDataProvider              TiktokDataProvider
+	telegramChannelPostsProvider    TelegramChannelPostsProvider
+	rutubeChannelVideosProvider     RutubeChannelVideosProvider
+	metricsHistory                  MetricsHistory
 }
 
 func NewUsecase(
@@ -30,6 +38,10 @@ func NewUsecase(
 	instagramGetReelsInfoForAccount InstagramGetReelsInfoForAccount,
 	youtubeChannelShortsData YoutubeChannelShortsData,
 	tiktokDataProvider TiktokDataProvider,
+	jobTracker JobTracker,
+	telegramChannelPostsProvider TelegramChannelPostsProvider,
+	rutubeChannelVideosProvider RutubeChannelVideosProvider,
+	metricsHistory MetricsHistory,
 ) *Usecase {
 	return &Usecase{
 		logger:                          log,
@@ -41,54 +53,103 @@ func NewUsecase(
 		instagramGetReelsInfoForAccount: instagramGetReelsInfoForAccount,
 		youtubeChannelShortsData:        youtubeChannelShortsData,
 		tiktokDataProvider:              tiktokDataProvider,
+		jobTracker:                      jobTracker,
+		telegramChannelPostsProvider:    telegramChannelPostsProvider,
+		rutubeChannelVideosProvider:     rutubeChannelVideosProvider,
+		metricsHistory:                  metricsHistory,
 	}
 }
 
 type (
 	AccountUrlsProvider interface {
 		AccountUrls(
+			ctx context.Context,
 			isSelected bool,
 			sheetName, spreadsheetID string,
 		) ([]*models.UrlInfo, error)
 	}
 
+	// методы *Concurrency задают размер пула аккаунтов платформы — по лимитеру провайдера
 	VKGroupIDProvider interface {
-		GroupID(groupName string) (string, error)
-		UserID(userName string) (string, error)
+		GroupID(ctx context.Context, groupName string) (string, error)
+		UserID(ctx context.Context, userName string) (string, error)
+		GroupProfile(ctx context.Context, groupName string) (*models.AccountProfile, error)
+		UserProfile(ctx context.Context, userName string) (*models.AccountProfile, error)
+		Concurrency() int
 	}
 
 	VKClipInfoProvider interface {
-		GetVKClipsInfoForGroup(info *models.AccountInfo) ([]*models.VKClipInfo, error)
+		GetVKClipsInfoForGroup(ctx context.Context, info *models.AccountInfo) ([]*models.VKClipInfo, error)
 	}
 
 	InstagramGetReelsInfoForAccount interface {
-		GetInstagramReelsInfoForAccount(info *models.AccountInfo) ([]*models.InstagramReelInfo, error)
+		GetInstagramReelsInfoForAccount(ctx context.Context, info *models.AccountInfo) ([]*models.InstagramReelInfo, error)
+		GetInstagramPostsInfoForAccount(ctx context.Context, info *models.AccountInfo) ([]*models.InstagramReelInfo, error)
+		GetInstagramProfile(ctx context.Context, username string) (*models.AccountProfile, error)
+		InstagramConcurrency() int
 	}
 
 	YoutubeChannelShortsData interface {
-		GetChannelIDByUsername(username string) (string, error)
-		GetShortsInfoByAccountName(accountInfo *models.AccountInfo) ([]*models.YoutubeShortInfoApiResponse, error)
+		GetChannel(ctx context.Context, username string) (string, *models.AccountProfile, error)
+		GetShortsInfoByAccountName(ctx context.Context, accountInfo *models.AccountInfo) ([]*models.YoutubeShortInfoApiResponse, error)
+		Concurrency() int
+	}
+
+	TelegramChannelPostsProvider interface {
+		GetTelegramChannelPosts(ctx context.Context, info *models.AccountInfo) ([]*models.TelegramPost, error)
+		Concurrency() int
+	}
+
+	RutubeChannelVideosProvider interface {
+		GetRutubeChannelVideos(ctx context.Context, info *models.AccountInfo) ([]*models.RutubeVideo, error)
+		Concurrency() int
+	}
+
+	// MetricsHistory история метрик роликов
+	MetricsHistory interface {
+		Append(snapshots []*models.MetricSnapshot) error
+		Series(key string) []*models.MetricSnapshot
 	}
 
 	TrackerService interface {
-		EnsureProgressSheet(spreadsheetID string) error
-		StartParsing(spreadsheetID string, totalURLs int) (int, error)
-		UpdateProgress(spreadsheetID string, row, progress int) error
-		FinishParsing(spreadsheetID string, row int) error
+		EnsureProgressSheet(ctx context.Context, spreadsheetID string) error
+		StartParsing(ctx context.Context, spreadsheetID string, totalURLs int) (int, error)
+		UpdateProgress(ctx context.Context, spreadsheetID string, row, progress int) error
+		FinishParsing(ctx context.Context, spreadsheetID string, row int) error
+		CancelParsing(ctx context.Context, spreadsheetID string, row int) error
+		InterruptParsing(ctx context.Context, spreadsheetID string, row int) error
+	}
+
+	JobTracker interface {
+		SetTotal(jobID string, total int)
+		SetProcessed(jobID string, processed int)
+		AddError(jobID string, msg string)
 	}
 
 	TiktokDataProvider interface {
-		GetTiktokAccountIdByUsername(username string) (string, error)
-		GetTiktokVideoByUserId(info *models.UrlInfo) ([]*models.TikTokVideo, error)
+		GetTiktokAccount(ctx context.Context, username string) (string, *models.AccountProfile, error)
+		GetTiktokVideoByUserId(ctx context.Context, info *models.UrlInfo) ([]*models.TikTokVideo, error)
+		TiktokConcurrency() int
 	}
 
 	DataInserter interface {
+		EnsureSheet(ctx context.Context, spreadsheetID, sheetName string, headers []interface{}) error
 		InsertData(
+			ctx context.Context,
 			spreadsheetID,
 			sheetName,
 			rangeData string,
 			data [][]interface{},
 		) error
+		UpsertData(
+			ctx context.Context,
+			spreadsheetID,
+			sheetName,
+			rangeData string,
+			keyColumn int,
+			data [][]interface{},
+			failed []bool,
+		) error
 	}
 )
 
@@ -98,9 +159,14 @@ const (
 )
 
 func (u *Usecase) ParseAccount(
+	ctx context.Context,
+	jobID string,
 	isSelected bool,
+	fullFeed bool,
+	window models.DateWindow,
+	upsert bool,
 	sheetName, spreadsheetID string,
-) {
+) error {
 	u.logger.Info("ParsingAccount request started",
 		slog.String("spreadsheet_id", spreadsheetID),
 		slog.String("sheet_name", sheetName),
@@ -110,13 +176,25 @@ func (u *Usecase) ParseAccount(
 		slog.String("sheet_name", sheetName),
 	)
 
-	if err := u.trackerService.EnsureProgressSheet(spreadsheetID); err != nil {
+	if err := u.trackerService.EnsureProgressSheet(ctx, spreadsheetID); err != nil {
 		u.logger.Error("Failed to ensure progress sheet",
 			slog.String("spreadsheet_id", spreadsheetID),
 		)
 	}
 
-	accountUrls, err := u.accountUrlsProvider.AccountUrls(isSelected, sheetName, spreadsheetID)
+	if err := u.dataInserter.EnsureSheet(
+		ctx,
+		spreadsheetID,
+		constants.AccountSummaryTable,
+		models.AccountSummaryHeaders,
+	); err != nil {
+		u.logger.Error("Failed to ensure account summary sheet",
+			slog.String("spreadsheet_id", spreadsheetID),
+			slog.String("err", err.Error()),
+		)
+	}
+
+	accountUrls, err := u.accountUrlsProvider.AccountUrls(ctx, isSelected, sheetName, spreadsheetID)
 	if err != nil {
 		u.logger.Error("Failed to find account urls",
 			slog.String("spreadsheet_id", spreadsheetID),
@@ -124,7 +202,7 @@ func (u *Usecase) ParseAccount(
 			slog.String("err", err.Error()),
 		)
 
-		return
+		return fmt.Errorf("failed to find account urls: %w", models.MarkTransient(err))
 	}
 
 	if len(accountUrls) == 0 {
@@ -133,16 +211,25 @@ func (u *Usecase) ParseAccount(
 			slog.String("sheet_name", sheetName),
 		)
 
-		return
+		return nil
 	}
 
+	// окно из запроса заменяет глубину из листа
+	if !window.IsZero() {
+		for _, accountUrl := range accountUrls {
+			accountUrl.Window = window
+		}
+	}
+
+	u.jobTracker.SetTotal(jobID, len(accountUrls))
+
 	u.logger.Info("Find groups urls successfully",
 		slog.Int("count", len(accountUrls)),
 		slog.String("spreadsheet_id", spreadsheetID),
 		slog.String("sheet_name", sheetName),
 	)
 
-	progressRow, errStartParsing := u.trackerService.StartParsing(spreadsheetID, len(accountUrls))
+	progressRow, errStartParsing := u.trackerService.StartParsing(ctx, spreadsheetID, len(accountUrls))
 	if errStartParsing != nil {
 		u.logger.Error("Error starting progress tracking",
 			slog.String("spreadsheet_id", spreadsheetID),
@@ -150,15 +237,25 @@ func (u *Usecase) ParseAccount(
 		)
 	}
 	defer func() {
-		if err = u.trackerService.FinishParsing(spreadsheetID, progressRow); err != nil {
+		// после отмены контекст задачи уже закрыт, поэтому итог пишем без отмены
+		finishCtx := context.WithoutCancel(ctx)
+		finish := u.trackerService.FinishParsing
+		switch {
+		case errors.Is(context.Cause(ctx), models.ErrShutdown):
+			finish = u.trackerService.InterruptParsing
+		case ctx.Err() != nil:
+			finish = u.trackerService.CancelParsing
+		}
+
+		if err := finish(finishCtx, spreadsheetID, progressRow); err != nil {
 			u.logger.Error("Error finishing progress tracking",
 				slog.String("spreadsheet_id", spreadsheetID),
-				slog.String("err", errStartParsing.Error()),
+				slog.String("err", err.Error()),
 			)
 		}
 	}()
 
-	var processedCount int
+	jobs := make([]*accountJob, 0, len(accountUrls))
 	for _, accountUrl := range accountUrls {
 		accountName, parsingType, err := models.ParseSocialAccountURL(accountUrl.URL)
 		if err != nil {
@@ -167,113 +264,34 @@ func (u *Usecase) ParseAccount(
 				slog.String("err", err.Error()),
 			)
 
-			return
+			return fmt.Errorf("failed to parse account url %s: %w", accountUrl.URL, err)
 		}
 
-		switch parsingType {
-		case models.VKGroupParsingType:
-			result, processVkGroupErr := u.processVKGroup(
-				accountName,
-				accountUrl,
-			)
-			if processVkGroupErr != nil {
-				u.logger.Error("Failed to get clips info",
-					slog.String("spreadsheet_id", spreadsheetID),
-					slog.String("sheet_name", sheetName),
-					slog.String("accountName", accountName),
-					slog.String("err", processVkGroupErr.Error()),
-				)
-				continue
-			}
-
-			if insertErr := u.dataInserter.InsertData(
-				spreadsheetID,
-				constants.AccountTable,
-				"A:I",
-				models.VKClipsInfoToInterface(result),
-			); insertErr != nil {
-				u.logger.Error("Failed to insert groups data", slog.String("err", insertErr.Error()))
-			}
-		case models.InstagramParsingType:
-			result, processInstagramReelErr := u.processInstagramAccount(
-				accountName,
-				accountUrl,
-			)
-			if processInstagramReelErr != nil {
-				u.logger.Error("Failed to get reel info",
-					slog.String("spreadsheet_id", spreadsheetID),
-					slog.String("sheet_name", sheetName),
-					slog.String("accountName", accountName),
-					slog.String("err", processInstagramReelErr.Error()),
-				)
-				continue
-			}
-
-			if insertErr := u.dataInserter.InsertData(
-				spreadsheetID,
-				constants.AccountTable,
-				"A:I",
-				models.InstagramReelInfoToInterface(result),
-			); insertErr != nil {
-				u.logger.Error("Failed to insert groups data", slog.String("err", insertErr.Error()))
-			}
-
-		case models.YoutubeParsingType:
-			result, processYoutubeErr := u.processYoutubeAccount(
-				accountName,
-				accountUrl,
-			)
-			if processYoutubeErr != nil {
-				u.logger.Error("Failed to get shoers info",
-					slog.String("spreadsheet_id", spreadsheetID),
-					slog.String("sheet_name", sheetName),
-					slog.String("accountName", accountName),
-					slog.String("err", processYoutubeErr.Error()),
-				)
-
-				continue
-			}
-
-			if insertErr := u.dataInserter.InsertData(
-				spreadsheetID,
-				constants.AccountTable,
-				"A:I",
-				models.YoutubeShortInfoApiResponseToInterface(result, accountUrl.URL),
-			); insertErr != nil {
-				u.logger.Error("Failed to insert groups data", slog.String("err", insertErr.Error()))
-			}
-		case models.TiktokParsingType:
-			result, err := u.processTikTokAccount(
-				accountName,
-				accountUrl,
-			)
-			if err != nil {
-				u.logger.Error("Failed to get tiktok video info",
-					slog.String("account_name", accountName),
-					slog.String("err", err.Error()),
-				)
-			}
-
-			if insertErr := u.dataInserter.InsertData(
-				spreadsheetID,
-				constants.AccountTable,
-				"A:I",
-				models.TikTokVideoApiResponseToInterface(result, accountUrl.URL),
-			); insertErr != nil {
-				u.logger.Error("Failed to insert groups data", slog.String("err", insertErr.Error()))
-			}
-		}
+		jobs = append(jobs, &accountJob{
+			url:         accountUrl,
+			name:        accountName,
+			parsingType: parsingType,
+		})
+	}
 
-		processedCount++
-		if updateProgressErr := u.trackerService.UpdateProgress(spreadsheetID, progressRow, processedCount); updateProgressErr != nil {
-			u.logger.Error("Error updating progress", err)
-		}
+	processedCount := u.processAccounts(ctx, jobID, jobs, fullFeed, upsert, progressRow, sheetName, spreadsheetID)
+	if ctx.Err() != nil {
+		u.logger.Warn("ParsingAccount cancelled",
+			slog.String("spreadsheet_id", spreadsheetID),
+			slog.Int("processed", processedCount),
+		)
+		return ctx.Err()
 	}
+
+	return nil
 }
 
 func (u *Usecase) ClipMoneyParseAccount(
+	ctx context.Context,
 	accountUrl string,
-) ([]*models.ClipMoneyResultRow, error) {
+	fullFeed bool,
+	window models.DateWindow,
+) ([]*models.ClipMoneyResultRow, *models.AccountProfile, error) {
 	u.logger.Info("ClipMoneyParseAccount request started",
 		slog.String("account_url", accountUrl),
 	)
@@ -288,14 +306,38 @@ func (u *Usecase) ClipMoneyParseAccount(
 			slog.String("err", err.Error()),
 		)
 
-		return nil, err
+		return nil, nil, err
+	}
+
+	urlInfo := models.DefaultUrlInfo(accountUrl)
+	urlInfo.Window = window
+
+	rows, fetched, err := u.clipMoneyAccountRows(ctx, accountName, parsingType, urlInfo, fullFeed)
+	if err != nil {
+		return nil, nil, err
 	}
+	u.recordHistory(rows)
+
+	return rows, u.accountProfile(ctx, accountName, parsingType, accountUrl, fetched, rows), nil
+}
+
+// clipMoneyAccountRows публикации аккаунта в формате ClipMoney и профиль,
+// если платформа отдаёт его вместе с поиском аккаунта
+func (u *Usecase) clipMoneyAccountRows(
+	ctx context.Context,
+	accountName string,
+	parsingType models.ParsingType,
+	urlInfo *models.UrlInfo,
+	fullFeed bool,
+) ([]*models.ClipMoneyResultRow, *models.AccountProfile, error) {
+	accountUrl := urlInfo.URL
 
 	switch parsingType {
 	case models.VKGroupParsingType:
 		result, processVkGroupErr := u.processVKGroup(
+			ctx,
 			accountName,
-			models.DefaultUrlInfo(accountUrl),
+			urlInfo,
 		)
 		if processVkGroupErr != nil {
 			u.logger.Error("Failed to get clips info",
@@ -304,11 +346,13 @@ func (u *Usecase) ClipMoneyParseAccount(
 			)
 		}
 
-		return models.ClipMoneyResultRowFromVkClipInfo(result, accountUrl), nil
+		return models.ClipMoneyResultRowFromVkClipInfo(result, accountUrl), nil, nil
 	case models.InstagramParsingType:
 		result, processInstagramReelErr := u.processInstagramAccount(
+			ctx,
 			accountName,
-			models.DefaultUrlInfo(accountUrl),
+			urlInfo,
+			fullFeed,
 		)
 		if processInstagramReelErr != nil {
 			u.logger.Error("Failed to get reel info",
@@ -317,12 +361,13 @@ func (u *Usecase) ClipMoneyParseAccount(
 			)
 		}
 
-		return models.ClipMoneyResultRowFromInstagramReelInfo(result, accountUrl), nil
+		return models.ClipMoneyResultRowFromInstagramReelInfo(result, accountUrl), nil, nil
 
 	case models.YoutubeParsingType:
-		result, processYoutubeErr := u.processYoutubeAccount(
+		result, profile, processYoutubeErr := u.processYoutubeAccount(
+			ctx,
 			accountName,
-			models.DefaultUrlInfo(accountUrl),
+			urlInfo,
 		)
 		if processYoutubeErr != nil {
 			u.logger.Error("Failed to get shorts info",
@@ -331,13 +376,15 @@ func (u *Usecase) ClipMoneyParseAccount(
 			)
 		}
 
-		return models.ClipMoneyResultRowFromYoutubeShortInfoApiResponse(result, accountUrl), nil
+		return models.ClipMoneyResultRowFromYoutubeShortInfoApiResponse(result, accountUrl), profile, nil
 	case models.TiktokParsingType:
-		result, err := u.processTikTokAccount(
+		result, profile, err := u.processTikTokAccount(
+			ctx,
 			accountName,
 			&models.UrlInfo{
-				URL:   accountUrl,
-				Count: 30,
+				URL:    accountUrl,
+				Count:  30,
+				Window: urlInfo.Window,
 			},
 		)
 		if err != nil {
@@ -347,14 +394,43 @@ func (u *Usecase) ClipMoneyParseAccount(
 			)
 		}
 
-		return models.ClipMoneyResultRowFromTiktokVideo(result, accountUrl, accountName), nil
+		return models.ClipMoneyResultRowFromTiktokVideo(result, accountUrl, accountName), profile, nil
+	case models.TelegramParsingType:
+		result, err := u.processTelegramChannel(
+			ctx,
+			accountName,
+			urlInfo,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get telegram posts",
+				slog.String("account_name", accountName),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		return models.ClipMoneyResultRowFromTelegramPosts(result, accountUrl), nil, nil
+	case models.RutubeParsingType:
+		result, err := u.processRutubeChannel(
+			ctx,
+			accountName,
+			urlInfo,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get rutube videos",
+				slog.String("account_name", accountName),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		return models.ClipMoneyResultRowFromRutubeVideos(result, accountUrl), nil, nil
 	}
 
-	return nil, fmt.Errorf("unknown parsingType: %s", parsingType)
+	return nil, nil, fmt.Errorf("unknown parsingType: %s", parsingType)
 }
 
 // processVKGroup общая точка обработки групп вк для таблиц и апи
 func (u *Usecase) processVKGroup(
+	ctx context.Context,
 	accountName string,
 	accountUrl *models.UrlInfo,
 ) ([]*models.VKClipInfo, error) {
@@ -362,14 +438,14 @@ func (u *Usecase) processVKGroup(
 	if _, err := strconv.Atoi(accountName); err == nil {
 		groupID = accountName
 	} else {
-		groupID, err = u.vkGroupIDProvider.GroupID(accountName)
+		groupID, err = u.vkGroupIDProvider.GroupID(ctx, accountName)
 		if err != nil {
 			u.logger.Error("Failed to get group id",
 				slog.String("account_name", accountName),
 				slog.String("err", err.Error()),
 			)
 
-			groupID, err = u.vkGroupIDProvider.UserID(accountName)
+			groupID, err = u.vkGroupIDProvider.UserID(ctx, accountName)
 			if err != nil {
 				u.logger.Error("Failed to get user id",
 					slog.String("account_name", accountName),
@@ -380,7 +456,7 @@ func (u *Usecase) processVKGroup(
 		}
 	}
 	//if _, err := strconv.Atoi(accountName); err == nil {
-	//	groupID, err = u.vkGroupIDProvider.GroupID(accountName)
+	//	groupID, err = u.vkGroupIDProvider.GroupID(ctx, accountName)
 	//	if err != nil {
 	//		u.logger.Error("Failed to get group id",
 	//			slog.String("account_name", accountName),
@@ -400,14 +476,14 @@ func (u *Usecase) processVKGroup(
 	//		groupID = accountName
 	//	}
 	//} else {
-	//	groupID, err = u.vkGroupIDProvider.GroupID(accountName)
+	//	groupID, err = u.vkGroupIDProvider.GroupID(ctx, accountName)
 	//	if err != nil {
 	//		u.logger.Error("Failed to get group id",
 	//			slog.String("account_name", accountName),
 	//			slog.String("err", err.Error()),
 	//		)
 	//
-	//		groupID, err = u.vkGroupIDProvider.UserID(accountName)
+	//		groupID, err = u.vkGroupIDProvider.UserID(ctx, accountName)
 	//		if err != nil {
 	//			u.logger.Error("Failed to get user id",
 	//				slog.String("account_name", accountName),
@@ -419,24 +495,32 @@ func (u *Usecase) processVKGroup(
 	//}
 
 	return u.vkClipInfoProvider.GetVKClipsInfoForGroup(
+		ctx,
 		getAccountInfo(groupID, models.VKGroupParsingType, accountUrl),
 	)
 }
 
 func (u *Usecase) processInstagramAccount(
+	ctx context.Context,
 	accountName string,
 	accountUrl *models.UrlInfo,
+	fullFeed bool,
 ) ([]*models.InstagramReelInfo, error) {
-	return u.instagramGetReelsInfoForAccount.GetInstagramReelsInfoForAccount(
-		getAccountInfo(accountName, models.InstagramParsingType, accountUrl),
-	)
+	info := getAccountInfo(accountName, models.InstagramParsingType, accountUrl)
+	if fullFeed {
+		return u.instagramGetReelsInfoForAccount.GetInstagramPostsInfoForAccount(ctx, info)
+	}
+
+	return u.instagramGetReelsInfoForAccount.GetInstagramReelsInfoForAccount(ctx, info)
 }
 
+// processYoutubeAccount ролики канала и профиль из того же запроса, что и плейлист загрузок
 func (u *Usecase) processYoutubeAccount(
+	ctx context.Context,
 	accountName string,
 	accountUrl *models.UrlInfo,
-) ([]*models.YoutubeShortInfoApiResponse, error) {
-	chanelID, err := u.youtubeChannelShortsData.GetChannelIDByUsername(accountName)
+) ([]*models.YoutubeShortInfoApiResponse, *models.AccountProfile, error) {
+	chanelID, profile, err := u.youtubeChannelShortsData.GetChannel(ctx, accountName)
 	if err != nil {
 		u.logger.Error("Failed to get chanel id by username",
 			slog.String("account_name", accountName),
@@ -446,23 +530,135 @@ func (u *Usecase) processYoutubeAccount(
 	accountInfo := getAccountInfo(accountName, models.YoutubeParsingType, accountUrl)
 	accountInfo.Identification = chanelID
 
-	return u.youtubeChannelShortsData.GetShortsInfoByAccountName(accountInfo)
+	shorts, err := u.youtubeChannelShortsData.GetShortsInfoByAccountName(ctx, accountInfo)
+
+	return shorts, profile, err
 }
 
+// processTikTokAccount видео аккаунта и профиль из того же поиска, что и ID аккаунта
 func (u *Usecase) processTikTokAccount(
+	ctx context.Context,
 	accountName string,
 	accountUrl *models.UrlInfo,
-) ([]*models.TikTokVideo, error) {
+) ([]*models.TikTokVideo, *models.AccountProfile, error) {
 	// get account id
-	userID, err := u.tiktokDataProvider.GetTiktokAccountIdByUsername(accountName)
+	userID, profile, err := u.tiktokDataProvider.GetTiktokAccount(ctx, accountName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return u.tiktokDataProvider.GetTiktokVideoByUserId(&models.UrlInfo{
-		URL:   userID,
-		Count: accountUrl.Count,
+	accountInfo := getAccountInfo(userID, models.TiktokParsingType, accountUrl)
+
+	videos, err := u.tiktokDataProvider.GetTiktokVideoByUserId(ctx, &models.UrlInfo{
+		URL:    userID,
+		Count:  accountInfo.Count,
+		Window: accountInfo.Window,
 	})
+
+	return videos, profile, err
+}
+
+func (u *Usecase) processTelegramChannel(
+	ctx context.Context,
+	accountName string,
+	accountUrl *models.UrlInfo,
+) ([]*models.TelegramPost, error) {
+	return u.telegramChannelPostsProvider.GetTelegramChannelPosts(
+		ctx,
+		getAccountInfo(accountName, models.TelegramParsingType, accountUrl),
+	)
+}
+
+func (u *Usecase) processRutubeChannel(
+	ctx context.Context,
+	accountName string,
+	accountUrl *models.UrlInfo,
+) ([]*models.RutubeVideo, error) {
+	return u.rutubeChannelVideosProvider.GetRutubeChannelVideos(
+		ctx,
+		getAccountInfo(accountName, models.RutubeParsingType, accountUrl),
+	)
+}
+
+// accountProfile снимок профиля аккаунта с ER по подписчикам. YouTube и TikTok отдают
+// профиль вместе с поиском аккаунта — он приходит в fetched, повторно не запрашивается.
+// Для платформ без данных о подписчиках и при ошибке возвращает nil.
+func (u *Usecase) accountProfile(
+	ctx context.Context,
+	accountName string,
+	parsingType models.ParsingType,
+	accountUrl string,
+	fetched *models.AccountProfile,
+	rows []*models.ClipMoneyResultRow,
+) *models.AccountProfile {
+	var (
+		profile *models.AccountProfile
+		err     error
+	)
+
+	switch parsingType {
+	case models.VKGroupParsingType:
+		profile, err = u.vkProfile(ctx, accountName)
+	case models.InstagramParsingType:
+		profile, err = u.instagramGetReelsInfoForAccount.GetInstagramProfile(ctx, accountName)
+	case models.YoutubeParsingType, models.TiktokParsingType:
+		profile = fetched
+	default:
+		return nil
+	}
+	if err != nil {
+		u.logger.Warn("Failed to get account profile",
+			slog.String("account_name", accountName),
+			slog.String("err", err.Error()),
+		)
+
+		return nil
+	}
+	if profile == nil {
+		return nil
+	}
+
+	profile.AccountUrl = accountUrl
+	profile.ParsingDate = utils.ParsingDate()
+	profile.SetEngagement(rows)
+
+	return profile
+}
+
+// vkProfile профиль группы вк, если группы нет — профиль пользователя
+func (u *Usecase) vkProfile(ctx context.Context, accountName string) (*models.AccountProfile, error) {
+	profile, err := u.vkGroupIDProvider.GroupProfile(ctx, accountName)
+	if err == nil {
+		return profile, nil
+	}
+
+	return u.vkGroupIDProvider.UserProfile(ctx, accountName)
+}
+
+// recordHistory сохраняет снимки метрик публикаций аккаунта и проставляет в строки
+// прирост просмотров за сутки и неделю по прошлым снимкам
+func (u *Usecase) recordHistory(rows []*models.ClipMoneyResultRow) {
+	now := time.Now()
+
+	snapshots := make([]*models.MetricSnapshot, 0, len(rows))
+	for _, row := range rows {
+		snapshot := models.SnapshotFromClipMoneyRow(row, now)
+		if snapshot == nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+
+		series := u.metricsHistory.Series(snapshot.Key)
+		row.ViewsGain24h = models.ViewsGain(series, snapshot, 24*time.Hour)
+		row.ViewsGain7d = models.ViewsGain(series, snapshot, 7*24*time.Hour)
+	}
+
+	if err := u.metricsHistory.Append(snapshots); err != nil {
+		u.logger.Error("Failed to save metrics history",
+			slog.Int("snapshots", len(snapshots)),
+			slog.String("err", err.Error()),
+		)
+	}
 }
 
 func getAccountInfo(
@@ -470,11 +666,20 @@ func getAccountInfo(
 	parsingType models.ParsingType,
 	accountUrl *models.UrlInfo,
 ) *models.AccountInfo {
+	count := getCount(accountUrl.Count)
+	if !accountUrl.Window.Since.IsZero() {
+		// при окне с началом глубину ограничивает окно: лента останавливается на первой
+		// публикации старше Since, число публикаций — только предохранитель. Окно только
+		// с Until ленту не останавливает, поэтому глубина остаётся обычной.
+		count = maxCount
+	}
+
 	return &models.AccountInfo{
 		Identification: accountName,
 		ParsingType:    parsingType,
 		AccountUrl:     accountUrl.URL,
-		Count:          getCount(accountUrl.Count),
+		Count:          count,
+		Window:         accountUrl.Window,
 	}
 }
 
@@ -0,0 +1,36 @@
+package parsing_account
+
+import (
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+func Test_getAccountInfo(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	type args struct {
+		count  int
+		window models.DateWindow
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{name: "depth from sheet", args: args{count: 30}, want: 30},
+		{name: "default depth", want: defaultCount},
+		{name: "window with since", args: args{count: 30, window: models.DateWindow{Since: day}}, want: maxCount},
+		{name: "until only keeps depth", args: args{count: 30, window: models.DateWindow{Until: day}}, want: 30},
+		{name: "until only without depth", args: args{window: models.DateWindow{Until: day}}, want: defaultCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := &models.UrlInfo{URL: "https://t.me/channel", Count: tt.args.count, Window: tt.args.window}
+			if got := getAccountInfo("channel", models.TelegramParsingType, url).Count; got != tt.want {
+				t.Errorf("getAccountInfo().Count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
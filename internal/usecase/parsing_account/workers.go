@@ -0,0 +1,345 @@
+package parsing_account
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"inst_parser/internal/constants"
+	"inst_parser/internal/models"
+)
+
+const (
+	// maxAccountWorkers потолок пула аккаунтов платформы: аккаунт — это десятки
+	// последовательных запросов, больше параллельных аккаунтов лимитер всё равно не пропустит
+	maxAccountWorkers = 8
+	// accountBatchSize сколько готовых аккаунтов подряд копим перед записью в лист
+	accountBatchSize = 10
+)
+
+// accountJob аккаунт из листа с уже разобранной ссылкой
+type accountJob struct {
+	url         *models.UrlInfo
+	name        string
+	parsingType models.ParsingType
+}
+
+// accountResult строки аккаунта для записи в листы
+type accountResult struct {
+	rows    [][]interface{} // публикации для AccountTable
+	profile []interface{}   // строка сводки, nil — профиля нет
+}
+
+// processAccounts обрабатывает аккаунты пулами воркеров по платформам и пишет
+// результаты пачками в порядке строк исходного листа: пачка уходит, когда готовы
+// все аккаунты до неё. При отмене дописывает то, что успели собрать.
+// Возвращает число обработанных аккаунтов.
+func (u *Usecase) processAccounts(
+	ctx context.Context,
+	jobID string,
+	jobs []*accountJob,
+	fullFeed, upsert bool,
+	progressRow int,
+	sheetName, spreadsheetID string,
+) int {
+	results := make([]*accountResult, len(jobs))
+	done := make(chan int)
+
+	byPlatform := make(map[models.ParsingType][]int)
+	for i, job := range jobs {
+		byPlatform[job.parsingType] = append(byPlatform[job.parsingType], i)
+	}
+
+	var wg sync.WaitGroup
+	for parsingType, indexes := range byPlatform {
+		queue := make(chan int, len(indexes))
+		for _, i := range indexes {
+			queue <- i
+		}
+		close(queue)
+
+		workers := min(max(u.poolSize(parsingType), 1), maxAccountWorkers, len(indexes))
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := range queue {
+					if ctx.Err() != nil {
+						return
+					}
+
+					// каждый воркер пишет только в свои индексы, done передаёт результат сборщику
+					results[i] = u.processAccount(ctx, jobID, jobs[i], fullFeed, sheetName, spreadsheetID)
+					done <- i
+				}
+			}()
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ready := make([]bool, len(jobs))
+	var (
+		processedCount int
+		next           int
+		pending        []int
+	)
+	for i := range done {
+		ready[i] = true
+
+		// прогресс обновляет только сборщик, поэтому счётчик не скачет назад
+		processedCount++
+		u.jobTracker.SetProcessed(jobID, processedCount)
+		if err := u.trackerService.UpdateProgress(ctx, spreadsheetID, progressRow, processedCount); err != nil {
+			u.logger.Error("Error updating progress",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("err", err.Error()),
+			)
+		}
+
+		for next < len(jobs) && ready[next] {
+			pending = append(pending, next)
+			next++
+		}
+		if len(pending) >= accountBatchSize {
+			u.writeAccounts(ctx, jobID, spreadsheetID, upsert, jobs, results, pending)
+			pending = nil
+		}
+	}
+
+	// после отмены часть аккаунтов не обработана, готовые дописываем по порядку
+	for i := next; i < len(jobs); i++ {
+		if ready[i] {
+			pending = append(pending, i)
+		}
+	}
+	u.writeAccounts(ctx, jobID, spreadsheetID, upsert, jobs, results, pending)
+
+	return processedCount
+}
+
+// writeAccounts записывает аккаунты indexes одной вставкой в AccountTable и одной в сводку.
+// С upsert ролики, уже записанные в AccountTable, обновляются по ссылке на ролик
+// из второй колонки, иначе строки добавляются;
+// сводка — история снимков профиля, в неё строки всегда добавляются.
+func (u *Usecase) writeAccounts(
+	ctx context.Context,
+	jobID, spreadsheetID string,
+	upsert bool,
+	jobs []*accountJob,
+	results []*accountResult,
+	indexes []int,
+) {
+	var rows, profiles [][]interface{}
+	for _, i := range indexes {
+		rows = append(rows, results[i].rows...)
+		if results[i].profile != nil {
+			profiles = append(profiles, results[i].profile)
+		}
+	}
+
+	// после отмены контекст задачи закрыт, собранное всё равно пишем
+	writeCtx := context.WithoutCancel(ctx)
+	writeRows := u.dataInserter.InsertData
+	if upsert {
+		writeRows = func(ctx context.Context, spreadsheetID, sheetName, rangeData string, data [][]interface{}) error {
+			return u.dataInserter.UpsertData(ctx, spreadsheetID, sheetName, rangeData, constants.AccountTableKeyColumn, data, nil)
+		}
+	}
+	for _, insert := range []struct {
+		sheetName, rangeData string
+		data                 [][]interface{}
+		write                func(ctx context.Context, spreadsheetID, sheetName, rangeData string, data [][]interface{}) error
+	}{
+		{sheetName: constants.AccountTable, rangeData: "A:I", data: rows, write: writeRows},
+		{sheetName: constants.AccountSummaryTable, rangeData: "A:J", data: profiles, write: u.dataInserter.InsertData},
+	} {
+		if len(insert.data) == 0 {
+			continue
+		}
+
+		if err := insert.write(
+			writeCtx,
+			spreadsheetID,
+			insert.sheetName,
+			insert.rangeData,
+			insert.data,
+		); err != nil {
+			u.logger.Error("Failed to insert groups data",
+				slog.String("sheet_name", insert.sheetName),
+				slog.Int("accounts", len(indexes)),
+				slog.String("err", err.Error()),
+			)
+			for _, i := range indexes {
+				u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", jobs[i].url.URL, err))
+			}
+		}
+	}
+}
+
+// processAccount публикации и профиль одного аккаунта. Ошибки платформы попадают
+// в сводку задачи, аккаунт при этом считается обработанным.
+func (u *Usecase) processAccount(
+	ctx context.Context,
+	jobID string,
+	job *accountJob,
+	fullFeed bool,
+	sheetName, spreadsheetID string,
+) *accountResult {
+	accountName, accountUrl := job.name, job.url
+	result := &accountResult{}
+
+	// публикации аккаунта для ER по подписчикам в сводке и истории метрик
+	var rows []*models.ClipMoneyResultRow
+	// профиль, полученный вместе с поиском аккаунта
+	var fetched *models.AccountProfile
+
+	switch job.parsingType {
+	case models.VKGroupParsingType:
+		clips, err := u.processVKGroup(
+			ctx,
+			accountName,
+			accountUrl,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get clips info",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("accountName", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+			return result
+		}
+
+		result.rows = models.VKClipsInfoToInterface(clips)
+		rows = models.ClipMoneyResultRowFromVkClipInfo(clips, accountUrl.URL)
+	case models.InstagramParsingType:
+		reels, err := u.processInstagramAccount(
+			ctx,
+			accountName,
+			accountUrl,
+			fullFeed,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get reel info",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("accountName", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+			return result
+		}
+
+		result.rows = models.InstagramReelInfoToInterface(reels)
+		rows = models.ClipMoneyResultRowFromInstagramReelInfo(reels, accountUrl.URL)
+	case models.YoutubeParsingType:
+		shorts, profile, err := u.processYoutubeAccount(
+			ctx,
+			accountName,
+			accountUrl,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get shorts info",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("accountName", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+			return result
+		}
+
+		fetched = profile
+		result.rows = models.YoutubeShortInfoApiResponseToInterface(shorts, accountUrl.URL)
+		rows = models.ClipMoneyResultRowFromYoutubeShortInfoApiResponse(shorts, accountUrl.URL)
+	case models.TiktokParsingType:
+		videos, profile, err := u.processTikTokAccount(
+			ctx,
+			accountName,
+			accountUrl,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get tiktok video info",
+				slog.String("account_name", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+		}
+
+		fetched = profile
+		result.rows = models.TikTokVideoApiResponseToInterface(videos, accountUrl.URL, accountName)
+		rows = models.ClipMoneyResultRowFromTiktokVideo(videos, accountUrl.URL, accountName)
+	case models.TelegramParsingType:
+		posts, err := u.processTelegramChannel(
+			ctx,
+			accountName,
+			accountUrl,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get telegram posts",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("account_name", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+			return result
+		}
+
+		result.rows = models.TelegramPostsToInterface(posts, accountUrl.URL)
+		rows = models.ClipMoneyResultRowFromTelegramPosts(posts, accountUrl.URL)
+	case models.RutubeParsingType:
+		videos, err := u.processRutubeChannel(
+			ctx,
+			accountName,
+			accountUrl,
+		)
+		if err != nil {
+			u.logger.Error("Failed to get rutube videos",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("account_name", accountName),
+				slog.String("err", err.Error()),
+			)
+			u.jobTracker.AddError(jobID, fmt.Sprintf("%s: %v", accountUrl.URL, err))
+			return result
+		}
+
+		result.rows = models.RutubeVideosToInterface(videos, accountUrl.URL)
+		rows = models.ClipMoneyResultRowFromRutubeVideos(videos, accountUrl.URL)
+	}
+
+	u.recordHistory(rows)
+	result.rows = models.AccountRowsWithViewsGain(result.rows, rows)
+
+	if profile := u.accountProfile(ctx, accountName, job.parsingType, accountUrl.URL, fetched, rows); profile != nil {
+		result.profile = profile.ToInterface()
+	}
+
+	return result
+}
+
+func (u *Usecase) poolSize(parsingType models.ParsingType) int {
+	switch parsingType {
+	case models.InstagramParsingType:
+		return u.instagramGetReelsInfoForAccount.InstagramConcurrency()
+	case models.VKGroupParsingType:
+		return u.vkGroupIDProvider.Concurrency()
+	case models.YoutubeParsingType:
+		return u.youtubeChannelShortsData.Concurrency()
+	case models.TiktokParsingType:
+		return u.tiktokDataProvider.TiktokConcurrency()
+	case models.TelegramParsingType:
+		return u.telegramChannelPostsProvider.Concurrency()
+	case models.RutubeParsingType:
+		return u.rutubeChannelVideosProvider.Concurrency()
+	default:
+		return 1
+	}
+}
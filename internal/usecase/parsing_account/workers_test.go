@@ -0,0 +1,216 @@
+package parsing_account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"inst_parser/internal/constants"
+	"inst_parser/internal/models"
+)
+
+// fakeTelegram отдаёт один пост канала; чем раньше канал в листе, тем дольше ответ,
+// поэтому аккаунты завершаются в обратном порядке
+type fakeTelegram struct {
+	concurrency int
+	total       int
+
+	running atomic.Int32
+	mu      sync.Mutex
+	peak    int32
+}
+
+func (p *fakeTelegram) GetTelegramChannelPosts(_ context.Context, info *models.AccountInfo) ([]*models.TelegramPost, error) {
+	n := p.running.Add(1)
+	defer p.running.Add(-1)
+
+	p.mu.Lock()
+	p.peak = max(p.peak, n)
+	p.mu.Unlock()
+
+	index, _ := strconv.Atoi(info.Identification[len("ch"):])
+	time.Sleep(time.Duration(p.total-index) * time.Millisecond)
+	if index%5 == 4 {
+		return nil, errors.New("channel not found")
+	}
+
+	return []*models.TelegramPost{{Channel: info.Identification, ID: 1}}, nil
+}
+
+func (p *fakeTelegram) Concurrency() int { return p.concurrency }
+
+// fakeSheets запоминает вставки, колонки ключа обновлений и прогресс
+type fakeSheets struct {
+	mu         sync.Mutex
+	inserts    [][][]interface{}
+	keyColumns []int
+	progress   []int
+	processed  []int
+	errors     int
+}
+
+func (s *fakeSheets) EnsureSheet(context.Context, string, string, []interface{}) error { return nil }
+
+func (s *fakeSheets) InsertData(_ context.Context, _, sheetName, _ string, data [][]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sheetName == constants.AccountTable {
+		s.inserts = append(s.inserts, data)
+	}
+	return nil
+}
+
+func (s *fakeSheets) UpsertData(ctx context.Context, spreadsheetID, sheetName, rangeData string, keyColumn int, data [][]interface{}, _ []bool) error {
+	s.mu.Lock()
+	s.keyColumns = append(s.keyColumns, keyColumn)
+	s.mu.Unlock()
+	return s.InsertData(ctx, spreadsheetID, sheetName, rangeData, data)
+}
+
+func (s *fakeSheets) Append([]*models.MetricSnapshot) error { return nil }
+
+func (s *fakeSheets) Series(string) []*models.MetricSnapshot { return nil }
+
+func (s *fakeSheets) EnsureProgressSheet(context.Context, string) error { return nil }
+
+func (s *fakeSheets) StartParsing(context.Context, string, int) (int, error) { return 2, nil }
+
+func (s *fakeSheets) UpdateProgress(_ context.Context, _ string, _, progress int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progress = append(s.progress, progress)
+	return nil
+}
+
+func (s *fakeSheets) FinishParsing(context.Context, string, int) error    { return nil }
+func (s *fakeSheets) CancelParsing(context.Context, string, int) error    { return nil }
+func (s *fakeSheets) InterruptParsing(context.Context, string, int) error { return nil }
+
+func (s *fakeSheets) SetTotal(string, int) {}
+
+func (s *fakeSheets) SetProcessed(_ string, processed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed = append(s.processed, processed)
+}
+
+func (s *fakeSheets) AddError(string, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors++
+}
+
+func TestUsecase_processAccounts(t *testing.T) {
+	const total = 25
+
+	telegram := &fakeTelegram{concurrency: 3, total: total}
+	sheets := &fakeSheets{}
+	u := &Usecase{
+		logger:                       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		telegramChannelPostsProvider: telegram,
+		dataInserter:                 sheets,
+		trackerService:               sheets,
+		jobTracker:                   sheets,
+		metricsHistory:               sheets,
+	}
+
+	jobs := make([]*accountJob, total)
+	for i := range jobs {
+		name := fmt.Sprintf("ch%d", i)
+		jobs[i] = &accountJob{
+			url:         &models.UrlInfo{URL: "https://t.me/" + name, Count: 1},
+			name:        name,
+			parsingType: models.TelegramParsingType,
+		}
+	}
+
+	processed := u.processAccounts(context.Background(), "job", jobs, false, true, 2, "Sheet", "spreadsheet")
+	if processed != total {
+		t.Fatalf("processAccounts() = %d, want %d", processed, total)
+	}
+
+	// с upsert строки аккаунтов сопоставляются по ссылке на пост, а не на канал
+	for _, keyColumn := range sheets.keyColumns {
+		if keyColumn != constants.AccountTableKeyColumn {
+			t.Errorf("UpsertData() keyColumn = %d, want %d", keyColumn, constants.AccountTableKeyColumn)
+		}
+	}
+	if len(sheets.keyColumns) != len(sheets.inserts) {
+		t.Errorf("upserts = %d, want every account write to upsert (%d)", len(sheets.keyColumns), len(sheets.inserts))
+	}
+
+	// вставки пачками и строки в порядке листа, аккаунты с ошибкой без строк;
+	// первая колонка — ссылка на канал, вторая — на пост
+	if len(sheets.inserts) >= total/2 {
+		t.Errorf("inserts = %d, want batched writes", len(sheets.inserts))
+	}
+	var got []interface{}
+	for _, insert := range sheets.inserts {
+		for _, row := range insert {
+			if want := strings.TrimSuffix(fmt.Sprint(row[1]), "/1"); row[0] != want {
+				t.Errorf("row account = %v, want %v", row[0], want)
+			}
+			got = append(got, row[1])
+		}
+	}
+	var want []interface{}
+	for i := range total {
+		if i%5 != 4 {
+			want = append(want, fmt.Sprintf("https://t.me/ch%d/1", i))
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rows = %v, want %v", got, want)
+	}
+	if sheets.errors != total/5 {
+		t.Errorf("errors = %d, want %d", sheets.errors, total/5)
+	}
+
+	// прогресс растёт на единицу с каждым аккаунтом, включая аккаунты с ошибкой
+	for i, progress := range sheets.progress {
+		if progress != i+1 || sheets.processed[i] != i+1 {
+			t.Fatalf("progress[%d] = %d, processed = %d, want %d", i, progress, sheets.processed[i], i+1)
+		}
+	}
+	if len(sheets.progress) != total {
+		t.Errorf("progress updates = %d, want %d", len(sheets.progress), total)
+	}
+
+	if telegram.peak > 3 {
+		t.Errorf("telegram peak concurrency = %d, want <= 3", telegram.peak)
+	}
+}
+
+func TestUsecase_processAccountsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sheets := &fakeSheets{}
+	u := &Usecase{
+		logger:                       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		telegramChannelPostsProvider: &fakeTelegram{concurrency: 2, total: 1},
+		dataInserter:                 sheets,
+		trackerService:               sheets,
+		jobTracker:                   sheets,
+	}
+
+	jobs := []*accountJob{{
+		url:         &models.UrlInfo{URL: "https://t.me/ch0"},
+		name:        "ch0",
+		parsingType: models.TelegramParsingType,
+	}}
+	if processed := u.processAccounts(ctx, "job", jobs, false, false, 2, "Sheet", "spreadsheet"); processed != 0 {
+		t.Errorf("processAccounts() = %d, want 0 after cancel", processed)
+	}
+	if len(sheets.inserts) != 0 {
+		t.Errorf("inserts = %d, want none", len(sheets.inserts))
+	}
+}
@@ -0,0 +1,102 @@
+package parsing_urls
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"inst_parser/internal/models"
+)
+
+// contentGroup строки листа, ссылающиеся на один ролик: запрос к провайдеру один на группу
+type contentGroup struct {
+	fetch *models.UrlInfo
+	rows  []*models.UrlInfo
+}
+
+// groupByContent объединяет строки по ключу контента в порядке первого появления
+func groupByContent(urls []*models.UrlInfo) []*contentGroup {
+	groups := make([]*contentGroup, 0, len(urls))
+	byKey := make(map[string]*contentGroup, len(urls))
+
+	for _, url := range urls {
+		key := url.ContentKey()
+		if group, ok := byKey[key]; ok {
+			group.rows = append(group.rows, url)
+			continue
+		}
+
+		group := &contentGroup{
+			fetch: &models.UrlInfo{URL: url.FetchURL(), Count: url.Count},
+			rows:  []*models.UrlInfo{url},
+		}
+		byKey[key] = group
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// fanOut раздаёт результат группы всем её строкам, подставляя ссылку из строки
+func fanOut(groups []*contentGroup, results []*models.ResultRowUrl) ([]*models.UrlInfo, []*models.ResultRowUrl) {
+	var (
+		rows       []*models.UrlInfo
+		rowResults []*models.ResultRowUrl
+	)
+
+	for i, group := range groups {
+		for _, row := range group.rows {
+			rows = append(rows, row)
+
+			if results[i] == nil {
+				rowResults = append(rowResults, nil)
+				continue
+			}
+
+			result := *results[i]
+			result.URL = row.URL
+			result.CanonicalURL = row.FetchURL()
+			rowResults = append(rowResults, &result)
+		}
+	}
+
+	return rows, rowResults
+}
+
+// shortLinkWorkers сколько коротких ссылок раскрываем одновременно
+const shortLinkWorkers = 8
+
+// resolveShortLinks раскрывает короткие ссылки, чтобы они прошли обычный парсинг
+// и схлопнулись с полными ссылками на тот же ролик. Нераскрытая ссылка парсится как есть.
+func (u *Usecase) resolveShortLinks(ctx context.Context, urls []*models.UrlInfo) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, shortLinkWorkers)
+	)
+
+	for _, url := range urls {
+		if !url.NeedsResolve() {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resolved, err := u.shortLinkResolver.Resolve(ctx, url.URL)
+			if err != nil {
+				u.logger.Warn("Failed to resolve short link",
+					slog.String("url", url.URL),
+					slog.String("err", err.Error()),
+				)
+				return
+			}
+
+			url.SetResolved(resolved)
+		}()
+	}
+
+	wg.Wait()
+}
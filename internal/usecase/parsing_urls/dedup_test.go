@@ -0,0 +1,50 @@
+package parsing_urls
+
+import (
+	"testing"
+
+	"inst_parser/internal/models"
+)
+
+func TestGroupByContentFanOut(t *testing.T) {
+	urls := []*models.UrlInfo{
+		models.NewUrlInfo("https://www.instagram.com/p/ABC/", 12, 3),
+		models.NewUrlInfo("https://youtu.be/dQw4w9WgXcQ", 12, 4),
+		models.NewUrlInfo("instagram.com/reel/ABC?igsh=1", 12, 5),
+		models.NewUrlInfo("https://www.youtube.com/shorts/dQw4w9WgXcQ", 12, 6),
+	}
+
+	groups := groupByContent(urls)
+	if len(groups) != 2 {
+		t.Fatalf("groupByContent() groups = %d, want 2", len(groups))
+	}
+	if got := groups[0].fetch.URL; got != "https://www.instagram.com/p/ABC/" {
+		t.Errorf("groups[0].fetch.URL = %s, want canonical url of the first link", got)
+	}
+
+	results := []*models.ResultRowUrl{
+		{URL: groups[0].fetch.URL, Views: 10, Status: models.UrlStatusOK},
+		nil, // не обработан из-за отмены
+	}
+
+	rows, rowResults := fanOut(groups, results)
+	if len(rows) != 4 || len(rowResults) != 4 {
+		t.Fatalf("fanOut() = %d rows, %d results, want 4", len(rows), len(rowResults))
+	}
+
+	wantRows := []int{3, 5, 4, 6}
+	for i, row := range rows {
+		if row.Row != wantRows[i] {
+			t.Errorf("rows[%d].Row = %d, want %d", i, row.Row, wantRows[i])
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if rowResults[i] == nil || rowResults[i].URL != rows[i].URL || rowResults[i].Views != 10 {
+			t.Errorf("rowResults[%d] = %+v, want copy with url %s", i, rowResults[i], rows[i].URL)
+		}
+	}
+	if rowResults[2] != nil || rowResults[3] != nil {
+		t.Errorf("rowResults of unprocessed group = %v, %v, want nil", rowResults[2], rowResults[3])
+	}
+}
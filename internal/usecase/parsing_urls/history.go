@@ -0,0 +1,39 @@
+package parsing_urls
+
+import (
+	"log/slog"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+// recordHistory сохраняет снимки метрик результатов и проставляет в них прирост
+// просмотров за сутки и неделю по прошлым снимкам. Одинаковые ролики пачки дают один снимок.
+func (u *Usecase) recordHistory(results []*models.ResultRowUrl) {
+	now := time.Now()
+
+	snapshots := make([]*models.MetricSnapshot, 0, len(results))
+	byKey := make(map[string]*models.MetricSnapshot, len(results))
+	for _, result := range results {
+		snapshot := models.SnapshotFromResultRow(result, now)
+		if snapshot == nil {
+			continue
+		}
+
+		if _, ok := byKey[snapshot.Key]; !ok {
+			byKey[snapshot.Key] = snapshot
+			snapshots = append(snapshots, snapshot)
+		}
+
+		series := u.metricsHistory.Series(snapshot.Key)
+		result.ViewsGain24h = models.ViewsGain(series, snapshot, 24*time.Hour)
+		result.ViewsGain7d = models.ViewsGain(series, snapshot, 7*24*time.Hour)
+	}
+
+	if err := u.metricsHistory.Append(snapshots); err != nil {
+		u.logger.Error("Failed to save metrics history",
+			slog.Int("snapshots", len(snapshots)),
+			slog.String("err", err.Error()),
+		)
+	}
+}
@@ -1,10 +1,11 @@
 package parsing_urls
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
-	"time"
 
 	"inst_parser/internal/constants"
 	"inst_parser/internal/models"
@@ -14,11 +15,17 @@ type Usecase struct {
 	logger                    *slog.Logger
 	urlsProvider              UrlsProvider
 	trackerService            TrackerService
+	jobTracker                JobTracker
 	dataInserter              DataInserter
 	instagramReelInfoProvider InstagramReelInfoProvider
 	vkInfoProvider            VKInfoProvider
 	youtubeShortInfoProvider  YoutubeShortInfoProvider
 	tiktokVideoInfoProvider   TiktokVideoInfoProvider
+	checkpointStore           CheckpointStore
+	shortLinkResolver         ShortLinkResolver
+	telegramPostProvider      TelegramPostProvider
+	rutubeVideoProvider       RutubeVideoProvider
+	metricsHistory            MetricsHistory
 }
 
 func NewUsecase(
@@ -30,6 +37,12 @@ func NewUsecase(
 	trackerService TrackerService,
 	youtubeShortInfoProvider YoutubeShortInfoProvider,
 	tiktokVideoInfoProvider TiktokVideoInfoProvider,
+	jobTracker JobTracker,
+	checkpointStore CheckpointStore,
+	shortLinkResolver ShortLinkResolver,
+	telegramPostProvider TelegramPostProvider,
+	rutubeVideoProvider RutubeVideoProvider,
+	metricsHistory MetricsHistory,
 ) *Usecase {
 	return &Usecase{
 		logger:                    logger,
@@ -40,68 +53,135 @@ func NewUsecase(
 		trackerService:            trackerService,
 		youtubeShortInfoProvider:  youtubeShortInfoProvider,
 		tiktokVideoInfoProvider:   tiktokVideoInfoProvider,
+		jobTracker:                jobTracker,
+		checkpointStore:           checkpointStore,
+		shortLinkResolver:         shortLinkResolver,
+		telegramPostProvider:      telegramPostProvider,
+		rutubeVideoProvider:       rutubeVideoProvider,
+		metricsHistory:            metricsHistory,
 	}
 }
 
 type (
 	UrlsProvider interface {
 		FindUrls(
+			ctx context.Context,
 			isSelected bool,
 			parsingTypes []models.ParsingType,
 			sheetName, spreadsheetID string,
 		) ([]*models.UrlInfo, error)
+		MetricColumns(ctx context.Context, spreadsheetID, sheetName string) (models.MetricColumns, error)
 	}
 
 	TrackerService interface {
-		EnsureProgressSheet(spreadsheetID string) error
-		StartParsing(spreadsheetID string, totalURLs int) (int, error)
-		UpdateProgress(spreadsheetID string, row, progress int) error
-		FinishParsing(spreadsheetID string, row int) error
+		EnsureProgressSheet(ctx context.Context, spreadsheetID string) error
+		StartParsing(ctx context.Context, spreadsheetID string, totalURLs int) (int, error)
+		UpdateProgress(ctx context.Context, spreadsheetID string, row, progress int) error
+		FinishParsing(ctx context.Context, spreadsheetID string, row int) error
+		CancelParsing(ctx context.Context, spreadsheetID string, row int) error
+		InterruptParsing(ctx context.Context, spreadsheetID string, row int) error
 	}
 
+	JobTracker interface {
+		SetTotal(jobID string, total int)
+		SetProcessed(jobID string, processed int)
+		AddError(jobID string, msg string)
+		AddStatuses(jobID string, counts map[models.UrlStatus]int)
+	}
+
+	// методы *Concurrency возвращают размер пула воркеров платформы — по лимитеру провайдера
+
 	VKInfoProvider interface {
-		ClipInfo(ownerID, clipID int) (*models.VKClipInfo, error)
-		PostInfo(postID string) (*models.VKClipInfo, error)
+		ClipInfo(ctx context.Context, ownerID, clipID int) (*models.VKClipInfo, error)
+		PostInfo(ctx context.Context, postID string) (*models.VKClipInfo, error)
+		Concurrency() int
 	}
 
 	InstagramReelInfoProvider interface {
-		GetInstagramReelInfo(reelURL string) (*models.RealTimeScraperMediaInfoResponse, error)
+		GetInstagramReelInfo(ctx context.Context, reelURL string) (*models.RealTimeScraperMediaInfoResponse, error)
+		InstagramConcurrency() int
 	}
 
 	YoutubeShortInfoProvider interface {
-		YoutubeShortInfo(shortID string) (*models.YoutubeShortInfoApiResponse, error)
+		YoutubeShortInfo(ctx context.Context, shortID string) (*models.YoutubeShortInfoApiResponse, error)
+		YoutubeVideosInfo(ctx context.Context, videoIDs []string) (map[string]*models.YoutubeShortInfoApiResponse, error)
+		Concurrency() int
 	}
 
 	TiktokVideoInfoProvider interface {
-		GetTiktokVideoInfo(url string) (*models.TikTokVideoApiResponse, error)
+		GetTiktokVideoInfo(ctx context.Context, url string) (*models.TikTokVideoApiResponse, error)
+		TiktokConcurrency() int
+	}
+
+	TelegramPostProvider interface {
+		GetTelegramPost(ctx context.Context, channel string, postID int) (*models.TelegramPost, error)
+		Concurrency() int
+	}
+
+	RutubeVideoProvider interface {
+		GetRutubeVideo(ctx context.Context, videoID string) (*models.RutubeVideo, error)
+		Concurrency() int
+	}
+
+	// CheckpointStore ссылки, уже записанные задачей в таблицу, со статусами
+	CheckpointStore interface {
+		Parsed(jobID string) ([]string, map[models.UrlStatus]int, error)
+		Append(jobID string, urls []string, statuses map[models.UrlStatus]int) error
+		Delete(jobID string) error
+	}
+
+	// MetricsHistory история метрик роликов по ключу контента
+	MetricsHistory interface {
+		Append(snapshots []*models.MetricSnapshot) error
+		Series(key string) []*models.MetricSnapshot
+	}
+
+	ShortLinkResolver interface {
+		Resolve(ctx context.Context, url string) (string, error)
 	}
 
 	DataInserter interface {
 		InsertData(
+			ctx context.Context,
 			spreadsheetID,
 			sheetName,
 			rangeData string,
 			data [][]interface{},
 		) error
+		UpsertData(
+			ctx context.Context,
+			spreadsheetID,
+			sheetName,
+			rangeData string,
+			keyColumn int,
+			data [][]interface{},
+			failed []bool,
+		) error
+		UpdateCells(ctx context.Context, spreadsheetID string, cells []*models.CellValue) error
 	}
 )
 
 const batchSize = 50
 
 func (u *Usecase) ParseUrls(
-	isSelected bool,
+	ctx context.Context,
+	jobID string,
+	isSelected, writeBack, upsert bool,
 	sheetName, spreadsheetID string,
-) {
+) error {
 	u.logger.Info("ParseUrls started")
 	defer u.logger.Info("ParseUrls finished")
 
 	urls, err := u.urlsProvider.FindUrls(
+		ctx,
 		isSelected,
 		[]models.ParsingType{
 			models.InstagramParsingType,
 			models.VKGroupParsingType,
 			models.YoutubeParsingType,
 			models.TiktokParsingType,
+			models.TelegramParsingType,
+			models.RutubeParsingType,
 		},
 		sheetName,
 		spreadsheetID,
@@ -112,7 +192,7 @@ func (u *Usecase) ParseUrls(
 			slog.String("sheet_name", sheetName),
 			slog.String("err", err.Error()),
 		)
-		return
+		return fmt.Errorf("failed to find urls: %w", models.MarkTransient(err))
 	}
 
 	if len(urls) == 0 {
@@ -120,17 +200,46 @@ func (u *Usecase) ParseUrls(
 			slog.String("spreadsheet_id", spreadsheetID),
 			slog.String("sheet_name", sheetName),
 		)
-		return
+		return nil
+	}
+
+	// в режиме записи в исходные строки колонки метрик ищем до парсинга, чтобы не тратить квоты зря
+	var metricColumns models.MetricColumns
+	if writeBack {
+		metricColumns, err = u.urlsProvider.MetricColumns(ctx, spreadsheetID, sheetName)
+		if err != nil {
+			u.logger.Error("Failed to find metric columns",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("err", err.Error()),
+			)
+			return fmt.Errorf("failed to find metric columns: %w", models.MarkTransient(err))
+		}
 	}
 
-	if err = u.trackerService.EnsureProgressSheet(spreadsheetID); err != nil {
+	u.jobTracker.SetTotal(jobID, len(urls))
+
+	// после перезапуска задачи продолжаем с последней контрольной точки,
+	// сводка статусов собирается заново, поэтому статусы пропущенных ссылок возвращаем в неё
+	urls, skipped, skippedStatuses := u.skipParsed(jobID, urls)
+	u.jobTracker.AddStatuses(jobID, skippedStatuses)
+	if skipped > 0 {
+		u.logger.Info("ParseUrls resumed from checkpoint",
+			slog.String("job_id", jobID),
+			slog.String("spreadsheet_id", spreadsheetID),
+			slog.Int("skipped", skipped),
+			slog.Int("left", len(urls)),
+		)
+	}
+
+	if err = u.trackerService.EnsureProgressSheet(ctx, spreadsheetID); err != nil {
 		u.logger.Error("Failed to ensure progress sheet",
 			slog.String("spreadsheet_id", spreadsheetID),
 			slog.String("err", err.Error()),
 		)
 	}
 
-	progressRow, errStartParsing := u.trackerService.StartParsing(spreadsheetID, len(urls))
+	progressRow, errStartParsing := u.trackerService.StartParsing(ctx, spreadsheetID, skipped+len(urls))
 	if errStartParsing != nil {
 		u.logger.Error("Error starting progress tracking",
 			slog.String("spreadsheet_id", spreadsheetID),
@@ -138,29 +247,92 @@ func (u *Usecase) ParseUrls(
 		)
 	}
 	defer func() {
-		if err = u.trackerService.FinishParsing(spreadsheetID, progressRow); err != nil {
+		// после отмены контекст задачи уже закрыт, поэтому итог пишем без отмены
+		finishCtx := context.WithoutCancel(ctx)
+		finish := u.trackerService.FinishParsing
+		switch {
+		case errors.Is(context.Cause(ctx), models.ErrShutdown):
+			finish = u.trackerService.InterruptParsing
+		case ctx.Err() != nil:
+			finish = u.trackerService.CancelParsing
+		}
+
+		if err := finish(finishCtx, spreadsheetID, progressRow); err != nil {
 			u.logger.Error("Error finishing progress tracking",
 				slog.String("err", err.Error()),
 			)
 		}
 	}()
 
-	results := make([]*models.ResultRowUrl, 0, len(urls))
+	u.resolveShortLinks(ctx, urls)
 
-	var processedCount int
-	for i := 0; i < len(urls); i += batchSize {
+	// одинаковые ролики в разных формах ссылки запрашиваем один раз
+	groups := groupByContent(urls)
+	if len(groups) < len(urls) {
+		u.logger.Info("Duplicate urls collapsed",
+			slog.String("job_id", jobID),
+			slog.Int("rows", len(urls)),
+			slog.Int("unique", len(groups)),
+		)
+	}
+
+	processedCount := skipped
+	statuses := make(map[models.UrlStatus]int)
+	defer func() {
+		attrs := []any{
+			slog.String("job_id", jobID),
+			slog.String("spreadsheet_id", spreadsheetID),
+		}
+		for status, n := range statuses {
+			attrs = append(attrs, slog.Int(string(status), n))
+		}
+		u.logger.Info("ParseUrls summary", attrs...)
+	}()
+	for i := 0; i < len(groups) && ctx.Err() == nil; i += batchSize {
 		end := i + batchSize
-		if end > len(urls) {
-			end = len(urls)
+		if end > len(groups) {
+			end = len(groups)
+		}
+
+		batchGroups := groups[i:end]
+		fetch := make([]*models.UrlInfo, len(batchGroups))
+		for j, group := range batchGroups {
+			fetch[j] = group.fetch
 		}
 
-		batch := urls[i:end]
-		batchResults := u.processBatchUrl(batch)
-		results = append(results, batchResults...)
+		batch, batchResults := fanOut(batchGroups, u.processBatchUrl(ctx, fetch))
+		u.recordHistory(batchResults)
+
+		// каждую пачку пишем сразу, при отмене — то, что успели собрать
+		if err := u.writeResults(
+			context.WithoutCancel(ctx),
+			spreadsheetID,
+			sheetName,
+			metricColumns,
+			upsert,
+			batch,
+			batchResults,
+		); err != nil {
+			u.logger.Error("ParsingUrls URLs returned an error",
+				slog.String("spreadsheet_id", spreadsheetID),
+				slog.String("sheet_name", sheetName),
+				slog.String("err", err.Error()),
+			)
+			return fmt.Errorf("failed to insert data: %w", models.MarkTransient(err))
+		}
+
+		u.saveCheckpoint(jobID, batch, batchResults, ctx.Err() == nil)
+
+		batchStatuses := models.CountStatuses(batchResults)
+		u.jobTracker.AddStatuses(jobID, batchStatuses)
+		for status, n := range batchStatuses {
+			statuses[status] += n
+		}
 
 		processedCount += len(batch)
+		u.jobTracker.SetProcessed(jobID, processedCount)
 
-		if err := u.trackerService.UpdateProgress(spreadsheetID, progressRow, processedCount); err != nil {
+		if err := u.trackerService.UpdateProgress(ctx, spreadsheetID, progressRow, processedCount); err != nil {
 			u.logger.Error("Error updating progress",
 				slog.String("spreadsheet_id", spreadsheetID),
 				slog.String("sheet_name", sheetName),
@@ -169,108 +341,185 @@ func (u *Usecase) ParseUrls(
 		}
 	}
 
-	if err := u.dataInserter.InsertData(
-		spreadsheetID,
-		constants.DataTable,
-		"A:I",
-		models.ResultRowsToInterface(results),
-	); err != nil {
-		u.logger.Error("ParsingUrls URLs returned an error",
+	if ctx.Err() != nil {
+		u.logger.Warn("ParseUrls cancelled",
 			slog.String("spreadsheet_id", spreadsheetID),
-			slog.String("sheet_name", sheetName),
+			slog.Int("processed", processedCount),
+		)
+
+		// остановленную сервисом задачу продолжим при перезапуске, отменённую пользователем — нет
+		if errors.Is(context.Cause(ctx), models.ErrJobCancelled) {
+			u.deleteCheckpoint(jobID)
+		}
+
+		return ctx.Err()
+	}
+
+	u.deleteCheckpoint(jobID)
+
+	return nil
+}
+
+// writeResults записывает результаты пачки: в исходные строки листа, если найдены
+// колонки метрик, иначе в DataTable — добавляя в конец или, с upsert, обновляя
+// строки уже записанных роликов
+func (u *Usecase) writeResults(
+	ctx context.Context,
+	spreadsheetID, sheetName string,
+	metricColumns models.MetricColumns,
+	upsert bool,
+	batch []*models.UrlInfo,
+	results []*models.ResultRowUrl,
+) error {
+	if metricColumns == nil {
+		if upsert {
+			return u.dataInserter.UpsertData(
+				ctx,
+				spreadsheetID,
+				constants.DataTable,
+				"A:I",
+				constants.DataTableKeyColumn,
+				models.ResultRowsToInterface(results),
+				models.ResultRowsFailed(results),
+			)
+		}
+
+		return u.dataInserter.InsertData(
+			ctx,
+			spreadsheetID,
+			constants.DataTable,
+			"A:I",
+			models.ResultRowsToInterface(results),
+		)
+	}
+
+	var cells []*models.CellValue
+	for i, url := range batch {
+		cells = append(cells, metricColumns.WriteBackCells(sheetName, url.Row, results[i])...)
+	}
+
+	return u.dataInserter.UpdateCells(ctx, spreadsheetID, cells)
+}
+
+// skipParsed убирает ссылки, записанные в таблицу до перезапуска задачи,
+// и возвращает счётчики их статусов
+func (u *Usecase) skipParsed(jobID string, urls []*models.UrlInfo) ([]*models.UrlInfo, int, map[models.UrlStatus]int) {
+	parsed, statuses, err := u.checkpointStore.Parsed(jobID)
+	if err != nil {
+		u.logger.Error("Failed to load checkpoint",
+			slog.String("job_id", jobID),
 			slog.String("err", err.Error()),
 		)
+		return urls, 0, nil
+	}
+	if len(parsed) == 0 {
+		return urls, 0, nil
+	}
+
+	done := make(map[string]struct{}, len(parsed))
+	for _, url := range parsed {
+		done[url] = struct{}{}
+	}
+
+	left := make([]*models.UrlInfo, 0, len(urls))
+	for _, url := range urls {
+		if _, ok := done[url.URL]; ok {
+			continue
+		}
+		left = append(left, url)
+	}
+
+	return left, len(urls) - len(left), statuses
+}
+
+// saveCheckpoint отмечает записанные ссылки пачки. Если пачка прервана отменой,
+// отмечаем только ссылки с результатом, остальные обработаем при перезапуске.
+func (u *Usecase) saveCheckpoint(jobID string, batch []*models.UrlInfo, results []*models.ResultRowUrl, completed bool) {
+	urls := make([]string, 0, len(batch))
+	for i, url := range batch {
+		if completed || results[i] != nil {
+			urls = append(urls, url.URL)
+		}
+	}
+	if len(urls) == 0 {
 		return
 	}
+
+	if err := u.checkpointStore.Append(jobID, urls, models.CountStatuses(results)); err != nil {
+		u.logger.Error("Failed to save checkpoint",
+			slog.String("job_id", jobID),
+			slog.String("err", err.Error()),
+		)
+	}
+}
+
+func (u *Usecase) deleteCheckpoint(jobID string) {
+	if err := u.checkpointStore.Delete(jobID); err != nil {
+		u.logger.Error("Failed to delete checkpoint",
+			slog.String("job_id", jobID),
+			slog.String("err", err.Error()),
+		)
+	}
 }
 
 func (u *Usecase) ClipMoneyParseUrl(
+	ctx context.Context,
 	url string,
 ) (*models.ResultRowUrl, error) {
 	u.logger.Info("ClipMoneyParseUrl started")
 	defer u.logger.Info("ClipMoneyParseUrl finished")
 
-	result, err := u.processUrl(url)
+	result, err := u.processUrl(ctx, url)
 	if err != nil {
 		u.logger.Error("Error processing url",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
+	} else {
+		u.recordHistory([]*models.ResultRowUrl{result})
 	}
 
 	return result, err
 }
 
-func (u *Usecase) processBatchUrl(
-	urls []*models.UrlInfo,
-) []*models.ResultRowUrl {
-	const (
-		instaTimeout = 550 * time.Millisecond
-		vkTimeout    = 250 * time.Millisecond
-	)
-	results := make([]*models.ResultRowUrl, len(urls))
-
-	for i, url := range urls {
-		var resultRow *models.ResultRowUrl
-		switch models.ParsingTypeByUrl(url.URL) {
-		case models.InstagramParsingType:
-			resultRow = u.parseInstagram(url.URL)
-		case models.VKGroupParsingType:
-			resultRow = u.parseVK(url.URL)
-		case models.YoutubeParsingType:
-			resultRow = u.parseYoutubeShort(url.URL)
-		case models.TiktokParsingType:
-			resultRow = u.ParseTiktokVideo(url.URL)
-		default:
-			u.logger.Warn("Unsupported URL type",
-				slog.String("url", url.URL),
-			)
-			continue
-		}
-
-		if resultRow == nil {
-			continue
-		}
-		results[i] = resultRow
-	}
-
-	return results
-}
-
 func (u *Usecase) processUrl(
+	ctx context.Context,
 	url string,
 ) (*models.ResultRowUrl, error) {
-	const (
-		instaTimeout = 550 * time.Millisecond
-		vkTimeout    = 250 * time.Millisecond
-	)
-
 	var resultRow *models.ResultRowUrl
 	switch models.ParsingTypeByUrl(url) {
 	case models.InstagramParsingType:
-		resultRow = u.parseInstagram(url)
+		resultRow = u.parseInstagram(ctx, url)
 	case models.VKGroupParsingType:
-		resultRow = u.parseVK(url)
+		resultRow = u.parseVK(ctx, url)
 	case models.YoutubeParsingType:
-		resultRow = u.parseYoutubeShort(url)
+		resultRow = u.parseYoutube(ctx, url)
 	case models.TiktokParsingType:
-		resultRow = u.ParseTiktokVideo(url)
+		resultRow = u.ParseTiktokVideo(ctx, url)
+	case models.TelegramParsingType:
+		resultRow = u.parseTelegram(ctx, url)
+	case models.RutubeParsingType:
+		resultRow = u.parseRutube(ctx, url)
 	default:
 		return nil, fmt.Errorf("unsupported URL type: %s", models.ParsingTypeByUrl(url))
 	}
 
+	if resultRow != nil {
+		resultRow.SetDefaultStatus()
+	}
+
 	return resultRow, nil
 }
 
-func (u *Usecase) parseInstagram(url string) *models.ResultRowUrl {
-	data, err := u.instagramReelInfoProvider.GetInstagramReelInfo(url)
+func (u *Usecase) parseInstagram(ctx context.Context, url string) *models.ResultRowUrl {
+	data, err := u.instagramReelInfoProvider.GetInstagramReelInfo(ctx, url)
 	if err != nil {
 		u.logger.Warn("Error fetching instagram data",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	resultRow, err := models.ProcessInstagramResponse(data, url, false)
@@ -278,62 +527,63 @@ func (u *Usecase) parseInstagram(url string) *models.ResultRowUrl {
 		u.logger.Error("Error processing instagram response",
 			slog.String("err", err.Error()),
 		)
-		return nil
+		return models.FailedResultRow(url, err)
 	}
 
 	return resultRow
 }
 
-func (u *Usecase) parseVK(url string) *models.ResultRowUrl {
+func (u *Usecase) parseVK(ctx context.Context, url string) *models.ResultRowUrl {
 	if strings.Contains(url, "wall") {
-		return u.parseVkWall(url)
+		return u.parseVkWall(ctx, url)
 	}
 
-	if strings.Contains(url, "clip") {
-		return u.parseVkClip(url)
+	if strings.Contains(url, "clip") || strings.Contains(url, "video") {
+		return u.parseVkVideo(ctx, url)
 	}
 
-	return nil
+	return models.FailedResultRow(url, fmt.Errorf("%w: vk link is neither clip, video nor wall post", models.ErrUnsupportedURL))
 }
 
-func (u *Usecase) parseVkClip(url string) *models.ResultRowUrl {
-	ownerID, clipID, err := models.ParseVkClipURL(url)
+// parseVkVideo клипы и обычные видео, в том числе vkvideo.ru: video.get отдаёт их одинаково
+func (u *Usecase) parseVkVideo(ctx context.Context, url string) *models.ResultRowUrl {
+	ownerID, clipID, err := models.ParseVkVideoURL(url)
 	if err != nil {
-		u.logger.Error("Error parsing vk clip url",
+		u.logger.Error("Error parsing vk video url",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, fmt.Errorf("%w: %w", models.ErrUnsupportedURL, err))
 	}
 
-	result, err := u.vkInfoProvider.ClipInfo(ownerID, clipID)
+	result, err := u.vkInfoProvider.ClipInfo(ctx, ownerID, clipID)
 	if err != nil {
 		u.logger.Error("Error getting clip info",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	if result.PostID != 0 && result.ErID == "" {
-		postResult, err := u.vkInfoProvider.PostInfo(fmt.Sprintf("%d_%d", ownerID, result.PostID))
+		postResult, err := u.vkInfoProvider.PostInfo(ctx, fmt.Sprintf("%d_%d", ownerID, result.PostID))
 		if err != nil {
 			u.logger.Error("Error getting post info",
 				slog.String("url", url),
 				slog.Int("post_id", result.PostID),
 				slog.String("err", err.Error()),
 			)
+		} else {
+			result.ErID = postResult.ErID
 		}
-
-		result.ErID = postResult.ErID
 	}
 
 	return models.ProcessVKClipInfoToResultRow(url, result)
 }
 
-func (u *Usecase) parseVkWall(url string) *models.ResultRowUrl {
+func (u *Usecase) parseVkWall(ctx context.Context, url string) *models.ResultRowUrl {
 	postID, err := models.ExtractVKPostID(url)
 	if err != nil {
 		u.logger.Error("Error extracting post ID",
@@ -341,53 +591,98 @@ func (u *Usecase) parseVkWall(url string) *models.ResultRowUrl {
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, fmt.Errorf("%w: %w", models.ErrUnsupportedURL, err))
 	}
 
-	result, err := u.vkInfoProvider.PostInfo(postID)
+	result, err := u.vkInfoProvider.PostInfo(ctx, postID)
 	if err != nil {
 		u.logger.Error("Error getting post info",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	return models.ProcessVKClipInfoToResultRow(url, result)
 }
 
-func (u *Usecase) parseYoutubeShort(url string) *models.ResultRowUrl {
-	shortID, ok := models.ExtractYouTubeShortsID(url)
+// parseYoutube шортс, обычное видео или трансляция по ссылке любого вида
+func (u *Usecase) parseYoutube(ctx context.Context, url string) *models.ResultRowUrl {
+	videoID, ok := models.ExtractYouTubeVideoID(url)
 	if !ok {
-		u.logger.Error("failed to extract youtube short id from url",
+		u.logger.Error("failed to extract youtube video id from url",
 			slog.String("url", url),
 		)
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, fmt.Errorf("%w: no youtube video id", models.ErrUnsupportedURL))
 	}
 
-	result, err := u.youtubeShortInfoProvider.YoutubeShortInfo(shortID)
+	result, err := u.youtubeShortInfoProvider.YoutubeShortInfo(ctx, videoID)
 	if err != nil {
-		u.logger.Error("Error getting youtube short info",
+		u.logger.Error("Error getting youtube video info",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	return result.ToResultRow(url)
 }
 
-func (u *Usecase) ParseTiktokVideo(url string) *models.ResultRowUrl {
-	info, err := u.tiktokVideoInfoProvider.GetTiktokVideoInfo(url)
+func (u *Usecase) parseTelegram(ctx context.Context, url string) *models.ResultRowUrl {
+	channel, postID, ok := models.ParseTelegramPostURL(url)
+	if !ok {
+		u.logger.Error("failed to extract telegram post from url",
+			slog.String("url", url),
+		)
+		return models.FailedResultRow(url, fmt.Errorf("%w: no telegram post id", models.ErrUnsupportedURL))
+	}
+
+	post, err := u.telegramPostProvider.GetTelegramPost(ctx, channel, postID)
+	if err != nil {
+		u.logger.Error("Error getting telegram post",
+			slog.String("url", url),
+			slog.String("err", err.Error()),
+		)
+
+		return models.FailedResultRow(url, err)
+	}
+
+	return post.ToResultRow(url)
+}
+
+func (u *Usecase) parseRutube(ctx context.Context, url string) *models.ResultRowUrl {
+	videoID, ok := models.ExtractRutubeVideoID(url)
+	if !ok {
+		u.logger.Error("failed to extract rutube video id from url",
+			slog.String("url", url),
+		)
+		return models.FailedResultRow(url, fmt.Errorf("%w: no rutube video id", models.ErrUnsupportedURL))
+	}
+
+	video, err := u.rutubeVideoProvider.GetRutubeVideo(ctx, videoID)
+	if err != nil {
+		u.logger.Error("Error getting rutube video",
+			slog.String("url", url),
+			slog.String("err", err.Error()),
+		)
+
+		return models.FailedResultRow(url, err)
+	}
+
+	return video.ToResultRow(url)
+}
+
+func (u *Usecase) ParseTiktokVideo(ctx context.Context, url string) *models.ResultRowUrl {
+	info, err := u.tiktokVideoInfoProvider.GetTiktokVideoInfo(ctx, url)
 	if err != nil {
 		u.logger.Error("Error getting tiktok video info",
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
 
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	result, err := info.Data.ToResultRow(url)
@@ -396,7 +691,7 @@ func (u *Usecase) ParseTiktokVideo(url string) *models.ResultRowUrl {
 			slog.String("url", url),
 			slog.String("err", err.Error()),
 		)
-		return models.EmptyResultRow(url)
+		return models.FailedResultRow(url, err)
 	}
 
 	return result
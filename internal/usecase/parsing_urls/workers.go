@@ -0,0 +1,180 @@
+package parsing_urls
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"inst_parser/internal/models"
+)
+
+// processBatchUrl раскидывает ссылки пачки по пулам воркеров платформ. Пул каждой
+// платформы размером с лимитер её провайдера, поэтому медленный Instagram не
+// держит TikTok. results[i] соответствует urls[i]; nil — ссылка пропущена или
+// не обработана из-за отмены. Ссылки неподдерживаемых платформ получают статус unsupported.
+// YouTube идёт не пулом, а одним пакетным запросом на всю пачку.
+func (u *Usecase) processBatchUrl(
+	ctx context.Context,
+	urls []*models.UrlInfo,
+) []*models.ResultRowUrl {
+	results := make([]*models.ResultRowUrl, len(urls))
+
+	byPlatform := make(map[models.ParsingType][]int)
+	for i, url := range urls {
+		parsingType := models.ParsingTypeByUrl(url.URL)
+		if u.parseFunc(parsingType) == nil {
+			u.logger.Warn("Unsupported URL type",
+				slog.String("url", url.URL),
+			)
+			results[i] = models.FailedResultRow(url.URL, models.ErrUnsupportedURL)
+			continue
+		}
+		byPlatform[parsingType] = append(byPlatform[parsingType], i)
+	}
+
+	var wg sync.WaitGroup
+	for parsingType, indexes := range byPlatform {
+		if parsingType == models.YoutubeParsingType {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				u.parseYoutubeBatch(ctx, urls, indexes, results)
+			}()
+			continue
+		}
+
+		parse := u.parseFunc(parsingType)
+
+		jobs := make(chan int, len(indexes))
+		for _, i := range indexes {
+			jobs <- i
+		}
+		close(jobs)
+
+		workers := min(max(u.poolSize(parsingType), 1), len(indexes))
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := range jobs {
+					if ctx.Err() != nil {
+						return
+					}
+
+					resultRow := parse(ctx, urls[i].URL)
+					// ответ, прерванный отменой, не пишем пустой строкой
+					if resultRow == nil || ctx.Err() != nil {
+						continue
+					}
+					resultRow.SetDefaultStatus()
+					// каждый воркер пишет только в свои индексы
+					results[i] = resultRow
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	return results
+}
+
+// parseYoutubeBatch статистика всех YouTube-ссылок urls[indexes] через пакетный
+// запрос провайдера и запись строк в results по тем же индексам
+func (u *Usecase) parseYoutubeBatch(
+	ctx context.Context,
+	urls []*models.UrlInfo,
+	indexes []int,
+	results []*models.ResultRowUrl,
+) {
+	videoIDs := make([]string, 0, len(indexes))
+	idByIndex := make(map[int]string, len(indexes))
+	for _, i := range indexes {
+		videoID, ok := models.ExtractYouTubeVideoID(urls[i].URL)
+		if !ok {
+			u.logger.Error("failed to extract youtube video id from url",
+				slog.String("url", urls[i].URL),
+			)
+			results[i] = models.FailedResultRow(urls[i].URL, fmt.Errorf("%w: no youtube video id", models.ErrUnsupportedURL))
+			continue
+		}
+
+		if !slices.Contains(videoIDs, videoID) {
+			videoIDs = append(videoIDs, videoID)
+		}
+		idByIndex[i] = videoID
+	}
+
+	if len(videoIDs) == 0 {
+		return
+	}
+
+	infos, err := u.youtubeShortInfoProvider.YoutubeVideosInfo(ctx, videoIDs)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		u.logger.Error("Error getting youtube videos info",
+			slog.Int("videos", len(videoIDs)),
+			slog.Int("received", len(infos)),
+			slog.String("err", err.Error()),
+		)
+	}
+
+	for i, videoID := range idByIndex {
+		url := urls[i].URL
+
+		info, ok := infos[videoID]
+		switch {
+		case ok:
+			results[i] = info.ToResultRow(url)
+			results[i].SetDefaultStatus()
+		case err != nil:
+			// пачка с этим роликом не получена
+			results[i] = models.FailedResultRow(url, err)
+		default:
+			// API молча пропускает удалённые и приватные ролики
+			results[i] = models.FailedResultRow(url, fmt.Errorf("%w: видео с ID %s", models.ErrContentNotFound, videoID))
+		}
+	}
+}
+
+func (u *Usecase) parseFunc(parsingType models.ParsingType) func(ctx context.Context, url string) *models.ResultRowUrl {
+	switch parsingType {
+	case models.InstagramParsingType:
+		return u.parseInstagram
+	case models.VKGroupParsingType:
+		return u.parseVK
+	case models.YoutubeParsingType:
+		return u.parseYoutube
+	case models.TiktokParsingType:
+		return u.ParseTiktokVideo
+	case models.TelegramParsingType:
+		return u.parseTelegram
+	case models.RutubeParsingType:
+		return u.parseRutube
+	default:
+		return nil
+	}
+}
+
+func (u *Usecase) poolSize(parsingType models.ParsingType) int {
+	switch parsingType {
+	case models.InstagramParsingType:
+		return u.instagramReelInfoProvider.InstagramConcurrency()
+	case models.VKGroupParsingType:
+		return u.vkInfoProvider.Concurrency()
+	case models.YoutubeParsingType:
+		return u.youtubeShortInfoProvider.Concurrency()
+	case models.TiktokParsingType:
+		return u.tiktokVideoInfoProvider.TiktokConcurrency()
+	case models.TelegramParsingType:
+		return u.telegramPostProvider.Concurrency()
+	case models.RutubeParsingType:
+		return u.rutubeVideoProvider.Concurrency()
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,235 @@
+package parsing_urls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+// fakeProvider отвечает ошибкой с задержкой и считает одновременные запросы
+type fakeProvider struct {
+	concurrency int
+	delay       time.Duration
+
+	running atomic.Int32
+	mu      sync.Mutex
+	peak    int32
+}
+
+func (p *fakeProvider) call() error {
+	n := p.running.Add(1)
+	defer p.running.Add(-1)
+
+	p.mu.Lock()
+	p.peak = max(p.peak, n)
+	p.mu.Unlock()
+
+	time.Sleep(p.delay)
+
+	return errors.New("not found")
+}
+
+func (p *fakeProvider) GetInstagramReelInfo(context.Context, string) (*models.RealTimeScraperMediaInfoResponse, error) {
+	return nil, p.call()
+}
+
+func (p *fakeProvider) InstagramConcurrency() int { return p.concurrency }
+
+func (p *fakeProvider) GetTiktokVideoInfo(context.Context, string) (*models.TikTokVideoApiResponse, error) {
+	return nil, p.call()
+}
+
+func (p *fakeProvider) TiktokConcurrency() int { return p.concurrency }
+
+func TestUsecase_processBatchUrl(t *testing.T) {
+	instagram := &fakeProvider{concurrency: 2, delay: 20 * time.Millisecond}
+	tiktok := &fakeProvider{concurrency: 10}
+
+	u := &Usecase{
+		logger:                    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		instagramReelInfoProvider: instagram,
+		tiktokVideoInfoProvider:   tiktok,
+	}
+
+	var urls []*models.UrlInfo
+	for i := 0; i < 30; i++ {
+		switch i % 3 {
+		case 0:
+			urls = append(urls, &models.UrlInfo{URL: fmt.Sprintf("https://www.instagram.com/reel/%d", i)})
+		case 1:
+			urls = append(urls, &models.UrlInfo{URL: fmt.Sprintf("https://www.tiktok.com/@user/video/%d", i)})
+		default:
+			urls = append(urls, &models.UrlInfo{URL: fmt.Sprintf("https://example.com/%d", i)})
+		}
+	}
+
+	results := u.processBatchUrl(context.Background(), urls)
+
+	if len(results) != len(urls) {
+		t.Fatalf("processBatchUrl() len = %d, want %d", len(results), len(urls))
+	}
+	for i, result := range results {
+		if result == nil || result.URL != urls[i].URL {
+			t.Errorf("results[%d] = %v, want row for %s", i, result, urls[i].URL)
+			continue
+		}
+
+		wantStatus := models.UrlStatusNotFound
+		if i%3 == 2 {
+			wantStatus = models.UrlStatusUnsupported
+		}
+		if result.Status != wantStatus {
+			t.Errorf("results[%d].Status = %v, want %v", i, result.Status, wantStatus)
+		}
+	}
+
+	if instagram.peak > 2 {
+		t.Errorf("instagram peak concurrency = %d, want <= 2", instagram.peak)
+	}
+}
+
+func TestUsecase_processBatchUrlCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u := &Usecase{
+		logger:                  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		tiktokVideoInfoProvider: &fakeProvider{concurrency: 5},
+	}
+
+	results := u.processBatchUrl(ctx, []*models.UrlInfo{{URL: "https://www.tiktok.com/@user/video/1"}})
+	if results[0] != nil {
+		t.Errorf("results[0] = %v, want nil after cancel", results[0].URL)
+	}
+}
+
+// fakeYoutube отдаёт ролики из videos и считает пакетные запросы
+type fakeYoutube struct {
+	videos map[string]*models.YoutubeShortInfoApiResponse
+	calls  [][]string
+}
+
+func (y *fakeYoutube) YoutubeShortInfo(context.Context, string) (*models.YoutubeShortInfoApiResponse, error) {
+	return nil, errors.New("unexpected single video call")
+}
+
+func (y *fakeYoutube) YoutubeVideosInfo(_ context.Context, videoIDs []string) (map[string]*models.YoutubeShortInfoApiResponse, error) {
+	y.calls = append(y.calls, videoIDs)
+
+	infos := make(map[string]*models.YoutubeShortInfoApiResponse)
+	for _, id := range videoIDs {
+		if info, ok := y.videos[id]; ok {
+			infos[id] = info
+		}
+	}
+
+	return infos, nil
+}
+
+func (y *fakeYoutube) Concurrency() int { return 2 }
+
+func TestUsecase_processBatchUrlYoutube(t *testing.T) {
+	youtube := &fakeYoutube{
+		videos: map[string]*models.YoutubeShortInfoApiResponse{
+			"dQw4w9WgXcQ": {ID: "dQw4w9WgXcQ", ViewCount: "100", ContentType: models.YoutubeContentVideo},
+			"5CHd6h1-Zps": {ID: "5CHd6h1-Zps", ViewCount: "7", ContentType: models.YoutubeContentShort},
+		},
+	}
+
+	u := &Usecase{
+		logger:                   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		youtubeShortInfoProvider: youtube,
+	}
+
+	urls := []*models.UrlInfo{
+		{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		{URL: "https://youtube.com/shorts/5CHd6h1-Zps"},
+		{URL: "https://youtu.be/dQw4w9WgXcQ"},
+		{URL: "https://www.youtube.com/shorts/AAAAAAAAAAA"},
+		{URL: "https://www.youtube.com/@mnogadetna"},
+	}
+
+	results := u.processBatchUrl(context.Background(), urls)
+
+	if len(youtube.calls) != 1 {
+		t.Fatalf("YoutubeVideosInfo() calls = %d, want 1", len(youtube.calls))
+	}
+	if len(youtube.calls[0]) != 3 {
+		t.Errorf("YoutubeVideosInfo() ids = %v, want 3 unique ids", youtube.calls[0])
+	}
+
+	wantStatuses := []models.UrlStatus{
+		models.UrlStatusOK,
+		models.UrlStatusOK,
+		models.UrlStatusOK,
+		models.UrlStatusNotFound,
+		models.UrlStatusUnsupported,
+	}
+	for i, want := range wantStatuses {
+		if results[i] == nil {
+			t.Errorf("results[%d] = nil, want status %v", i, want)
+			continue
+		}
+		if results[i].URL != urls[i].URL {
+			t.Errorf("results[%d].URL = %v, want %v", i, results[i].URL, urls[i].URL)
+		}
+		if results[i].Status != want {
+			t.Errorf("results[%d].Status = %v, want %v", i, results[i].Status, want)
+		}
+	}
+
+	if results[0].Views != 100 || results[1].ContentType != string(models.YoutubeContentShort) {
+		t.Errorf("unexpected youtube rows: %+v, %+v", results[0], results[1])
+	}
+}
+
+// fakeVK отдаёт видео с ID из запроса
+type fakeVK struct{}
+
+func (fakeVK) ClipInfo(_ context.Context, ownerID, clipID int) (*models.VKClipInfo, error) {
+	return &models.VKClipInfo{OwnerID: ownerID, ClipID: clipID, Views: 10, DownloadURL: "https://vk.com/file.mp4"}, nil
+}
+
+func (fakeVK) PostInfo(context.Context, string) (*models.VKClipInfo, error) {
+	return nil, errors.New("unexpected post call")
+}
+
+func (fakeVK) Concurrency() int { return 2 }
+
+func TestUsecase_parseVK(t *testing.T) {
+	u := &Usecase{
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		vkInfoProvider: fakeVK{},
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus models.UrlStatus
+	}{
+		{name: "clip", url: "https://vk.com/clip-1_2", wantStatus: models.UrlStatusOK},
+		{name: "video", url: "https://vk.com/video-1_2", wantStatus: models.UrlStatusOK},
+		{name: "vkvideo.ru", url: "https://vkvideo.ru/video-1_2", wantStatus: models.UrlStatusOK},
+		{name: "group", url: "https://vk.com/club1", wantStatus: models.UrlStatusUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := u.parseVK(context.Background(), tt.url)
+			got.SetDefaultStatus()
+			if got.Status != tt.wantStatus {
+				t.Errorf("parseVK() status = %v, want %v (%s)", got.Status, tt.wantStatus, got.Reason)
+			}
+			if tt.wantStatus == models.UrlStatusOK && got.Views != 10 {
+				t.Errorf("parseVK() views = %v, want 10", got.Views)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"inst_parser/internal/models"
+)
+
+// Handler выполняет задачу вида с типизированными параметрами.
+// Возвращённая ошибка помечает задачу как failed (или retrying для models.ErrTransient).
+// Контекст отменяется при отмене задачи через Cancel и по истечении окна Shutdown.
+type Handler[P any] func(ctx context.Context, jobID string, payload P) error
+
+// KindOptions настройки вида задачи. Все поля необязательные.
+type KindOptions[P any] struct {
+	// Concurrency сколько задач вида выполняется одновременно, 0 — ограничено только MaxWorkers
+	Concurrency int
+	// LockKey задачи с одинаковым ключом (в том числе разных видов) выполняются по очереди
+	LockKey func(payload P) string
+	// DedupKey ожидающая задача вида с тем же ключом поглощает новую
+	DedupKey func(payload P) string
+	// Merge объединяет параметры ожидающей задачи с поглощённым запросом
+	Merge func(pending, incoming P) P
+	// Validate проверяет параметры при постановке в очередь
+	Validate func(payload P) error
+}
+
+// kind вид задачи со стёртым типом параметров
+type kind struct {
+	name        string
+	concurrency int
+	prepare     func(payload json.RawMessage) (prepared, error)
+	merge       func(pending, incoming json.RawMessage) (json.RawMessage, error)
+	handle      func(ctx context.Context, jobID string, payload json.RawMessage) error
+}
+
+// prepared проверенные параметры задачи и вычисленные по ним ключи
+type prepared struct {
+	payload  json.RawMessage
+	lockKey  string
+	dedupKey string
+}
+
+// Register регистрирует вид задачи. Вызывается до Restore и запуска Watcher.
+func Register[P any](q *Queue, name string, opts KindOptions[P], handler Handler[P]) {
+	decode := func(raw json.RawMessage) (P, error) {
+		var payload P
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return payload, fmt.Errorf("%w: %s", models.ErrInvalidPayload, err.Error())
+		}
+
+		return payload, nil
+	}
+
+	k := &kind{
+		name:        name,
+		concurrency: opts.Concurrency,
+		prepare: func(raw json.RawMessage) (prepared, error) {
+			payload, err := decode(raw)
+			if err != nil {
+				return prepared{}, err
+			}
+
+			if opts.Validate != nil {
+				if err = opts.Validate(payload); err != nil {
+					return prepared{}, fmt.Errorf("%w: %s", models.ErrInvalidPayload, err.Error())
+				}
+			}
+
+			// параметры храним в нормализованном виде, чтобы дубликаты совпадали побайтово
+			normalized, err := json.Marshal(payload)
+			if err != nil {
+				return prepared{}, fmt.Errorf("%w: %s", models.ErrInvalidPayload, err.Error())
+			}
+
+			p := prepared{payload: normalized}
+			if opts.LockKey != nil {
+				p.lockKey = opts.LockKey(payload)
+			}
+			if opts.DedupKey != nil {
+				p.dedupKey = opts.DedupKey(payload)
+			}
+
+			return p, nil
+		},
+		merge: func(pending, incoming json.RawMessage) (json.RawMessage, error) {
+			if opts.Merge == nil {
+				return pending, nil
+			}
+
+			pendingPayload, err := decode(pending)
+			if err != nil {
+				return nil, err
+			}
+			incomingPayload, err := decode(incoming)
+			if err != nil {
+				return nil, err
+			}
+
+			return json.Marshal(opts.Merge(pendingPayload, incomingPayload))
+		},
+		handle: func(ctx context.Context, jobID string, raw json.RawMessage) error {
+			payload, err := decode(raw)
+			if err != nil {
+				return err
+			}
+
+			return handler(ctx, jobID, payload)
+		},
+	}
+
+	q.mu.Lock()
+	q.kinds[name] = k
+	q.mu.Unlock()
+}
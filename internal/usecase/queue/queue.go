@@ -1,105 +1,700 @@
 package queue
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"sync"
+	"time"
 
 	"inst_parser/internal/models"
+
+	"github.com/google/uuid"
 )
 
 const (
 	QueueSize  = 100
 	MaxWorkers = 100
+
+	// jobsHistorySize сколько завершённых задач хранится для /jobs
+	jobsHistorySize = 1000
 )
 
+// RetryPolicy повтор задач, упавших на временной ошибке (models.ErrTransient).
+// Задержка растёт вдвое с каждой попыткой: BaseDelay, 2×BaseDelay, … но не больше MaxDelay.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// delay задержка перед повтором после attempt-й неудачной попытки
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, p.MaxDelay)
+}
+
+// Storage хранилище задач, переживающее рестарт
+type Storage interface {
+	Save(job *models.Job) error
+	Load() ([]*models.Job, error)
+	Rewrite(jobs []*models.Job) error
+}
+
 type Queue struct {
-	ch        chan models.QueueRequest
+	logger    *slog.Logger
+	storage   Storage
+	retry     RetryPolicy
+	notify    chan struct{}
 	semaphore chan struct{}
 
-	mu    sync.Mutex
-	locks map[string]chan struct{} // ID → канал-блокировка
+	// контексты задач наследуются от baseCtx, а не от контекста Watcher,
+	// чтобы остановка Watcher не обрывала уже выполняющиеся задачи
+	baseCtx    context.Context
+	baseCancel context.CancelCauseFunc
+	running    sync.WaitGroup
+
+	mu           sync.Mutex
+	kinds        map[string]*kind                   // зарегистрированные виды задач
+	pending      map[string][]string                // ключ блокировки → ID ожидающих задач в порядке постановки
+	rotation     []string                           // ключи блокировки с ожидающими задачами в порядке обхода
+	pendingCount int                                // всего ожидающих задач
+	busy         map[string]struct{}                // ключи блокировки с выполняющейся задачей
+	kindRunning  map[string]int                     // вид задачи → сколько выполняется
+	jobs         map[string]*models.Job             // job ID → состояние задачи
+	cancels      map[string]context.CancelCauseFunc // job ID → отмена выполняющейся задачи
 }
 
-func NewQueue() *Queue {
+func NewQueue(logger *slog.Logger, storage Storage, retry RetryPolicy) *Queue {
+	baseCtx, baseCancel := context.WithCancelCause(context.Background())
+
 	return &Queue{
-		ch:        make(chan models.QueueRequest, QueueSize),
-		semaphore: make(chan struct{}, MaxWorkers),
-		locks:     make(map[string]chan struct{}),
+		logger:      logger,
+		retry:       retry,
+		baseCtx:     baseCtx,
+		baseCancel:  baseCancel,
+		storage:     storage,
+		notify:      make(chan struct{}, 1),
+		semaphore:   make(chan struct{}, MaxWorkers),
+		kinds:       make(map[string]*kind),
+		pending:     make(map[string][]string),
+		busy:        make(map[string]struct{}),
+		kindRunning: make(map[string]int),
+		jobs:        make(map[string]*models.Job),
+		cancels:     make(map[string]context.CancelCauseFunc),
 	}
 }
 
-// Enqueue добавляет запрос в очередь.
-// Возвращает ошибку если очередь полна.
-func (q *Queue) Enqueue(req models.QueueRequest) error {
-	select {
-	case q.ch <- req:
-		return nil
-	default:
-		return fmt.Errorf("queue is full (max %d)", QueueSize)
+// Restore загружает задачи из хранилища. Ожидающие задачи возвращаются в очередь,
+// ждущие повтора — на таймер, прерванные падением или остановкой сервиса
+// перезапускаются (resumeInterrupted) или помечаются interrupted.
+func (q *Queue) Restore(resumeInterrupted bool) error {
+	jobs, err := q.storage.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
 	}
+
+	q.mu.Lock()
+	for _, job := range jobs {
+		if _, ok := q.kinds[job.Kind]; !ok && job.IsActive() {
+			// вид задачи больше не зарегистрирован — выполнить её нечем
+			now := time.Now()
+			job.State = models.JobStateFailed
+			job.FinishedAt = &now
+			job.AddError(fmt.Sprintf("%s: %s", models.ErrUnknownJobKind.Error(), job.Kind))
+		}
+
+		switch job.State {
+		case models.JobStateRunning, models.JobStateInterrupted:
+			if !resumeInterrupted {
+				if job.State == models.JobStateRunning {
+					now := time.Now()
+					job.State = models.JobStateInterrupted
+					job.FinishedAt = &now
+				}
+				break
+			}
+
+			job.State = models.JobStatePending
+			job.StartedAt = nil
+			job.FinishedAt = nil
+			job.Restarts++
+			q.push(job)
+		case models.JobStatePending:
+			q.push(job)
+		case models.JobStateRetrying:
+			var wait time.Duration
+			if job.RetryAt != nil {
+				wait = time.Until(*job.RetryAt)
+			}
+			q.scheduleRetry(job.ID, wait)
+		}
+
+		q.jobs[job.ID] = job
+	}
+	q.trimHistory()
+
+	snapshot := make([]*models.Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		snapshot = append(snapshot, job.Copy())
+	}
+	restored := q.pendingCount
+	q.mu.Unlock()
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].CreatedAt.Before(snapshot[j].CreatedAt)
+	})
+
+	if err = q.storage.Rewrite(snapshot); err != nil {
+		return fmt.Errorf("failed to compact jobs: %w", err)
+	}
+
+	q.logger.Info("Queue restored",
+		slog.Int("jobs", len(snapshot)),
+		slog.Int("pending", restored),
+	)
+	q.wakeUp()
+
+	return nil
 }
 
-// Watcher запускает цикл обработки очереди.
-// Завершается при отмене контекста.
-func (q *Queue) Watcher(
-	ctx context.Context,
-	executeUrls func(bool, string, string),
-	executeAccount func(bool, string, string),
-) {
+// Enqueue ставит задачу вида kind с параметрами payload в очередь и возвращает ID задачи.
+// Неизвестный вид — models.ErrUnknownJobKind, некорректные параметры — models.ErrInvalidPayload.
+// Если ожидающая задача того же вида с тем же ключом дедупликации уже есть, новая
+// не создаётся и возвращается ID ожидающей. Если очередь полна — models.ErrQueueFull.
+func (q *Queue) Enqueue(kindName string, payload any) (string, error) {
+	q.mu.Lock()
+	k, ok := q.kinds[kindName]
+	q.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", models.ErrUnknownJobKind, kindName)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrInvalidPayload, err.Error())
+	}
+
+	p, err := k.prepare(raw)
+	if err != nil {
+		return "", err
+	}
+
+	q.mu.Lock()
+	if duplicate := q.findPending(kindName, p.dedupKey); duplicate != nil {
+		merged, err := k.merge(duplicate.Payload, p.payload)
+		if err == nil && !bytes.Equal(merged, duplicate.Payload) {
+			duplicate.Payload = merged
+			q.save(duplicate)
+		}
+		q.mu.Unlock()
+
+		q.logger.Info("Duplicate request collapsed into pending job",
+			slog.String("job_id", duplicate.ID),
+			slog.String("kind", kindName),
+			slog.String("dedup_key", p.dedupKey),
+		)
+		return duplicate.ID, nil
+	}
+
+	if q.pendingCount >= QueueSize {
+		q.mu.Unlock()
+		return "", fmt.Errorf("%w (max %d)", models.ErrQueueFull, QueueSize)
+	}
+
+	job := &models.Job{
+		QueueRequest: models.QueueRequest{
+			ID:       uuid.NewString(),
+			Kind:     kindName,
+			Payload:  p.payload,
+			LockKey:  p.lockKey,
+			DedupKey: p.dedupKey,
+		},
+		State:     models.JobStatePending,
+		CreatedAt: time.Now(),
+	}
+	q.jobs[job.ID] = job
+	q.push(job)
+	q.trimHistory()
+	q.save(job)
+	q.mu.Unlock()
+
+	q.wakeUp()
+
+	return job.ID, nil
+}
+
+// Job возвращает копию задачи по ID
+func (q *Queue) Job(id string) (*models.Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, false
+	}
+
+	return job.Copy(), true
+}
+
+// Jobs возвращает задачи с ключом блокировки lockKey (или все, если он пустой), новые первыми.
+// Парсинги листов блокируются по ID таблицы.
+func (q *Queue) Jobs(lockKey string) []*models.Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]*models.Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if lockKey != "" && job.LockKey != lockKey {
+			continue
+		}
+		result = append(result, job.Copy())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	return result
+}
+
+// Cancel отменяет задачу: ожидающая сразу помечается cancelled,
+// у выполняющейся отменяется контекст, и она завершается, сохранив собранное.
+func (q *Queue) Cancel(id string) (*models.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return nil, models.ErrJobNotFound
+	}
+
+	if !job.IsActive() {
+		return nil, models.ErrJobNotActive
+	}
+
+	if cancel, running := q.cancels[id]; running {
+		cancel(models.ErrJobCancelled)
+		return job.Copy(), nil
+	}
+
+	// ожидающая или ждущая повтора: таймер повтора увидит, что задача уже не retrying
+	if job.State == models.JobStatePending {
+		q.remove(job)
+	}
+
+	now := time.Now()
+	job.State = models.JobStateCancelled
+	job.FinishedAt = &now
+	job.RetryAt = nil
+	q.save(job)
+
+	return job.Copy(), nil
+}
+
+// DeadLetter возвращает задачи с исчерпанными повторами, новые первыми
+func (q *Queue) DeadLetter(lockKey string) []*models.Job {
+	jobs := q.Jobs(lockKey)
+
+	result := make([]*models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if job.State == models.JobStateDeadLetter {
+			result = append(result, job)
+		}
+	}
+
+	return result
+}
+
+// Requeue возвращает упавшую или исчерпавшую повторы задачу в очередь
+// с тем же ID и обнулённым счётчиком попыток
+func (q *Queue) Requeue(id string) (*models.Job, error) {
+	q.mu.Lock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		q.mu.Unlock()
+		return nil, models.ErrJobNotFound
+	}
+
+	if job.State != models.JobStateDeadLetter && job.State != models.JobStateFailed {
+		q.mu.Unlock()
+		return nil, models.ErrJobNotRequeueable
+	}
+
+	if _, ok := q.kinds[job.Kind]; !ok {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownJobKind, job.Kind)
+	}
+
+	if q.pendingCount >= QueueSize {
+		q.mu.Unlock()
+		return nil, fmt.Errorf("%w (max %d)", models.ErrQueueFull, QueueSize)
+	}
+
+	job.State = models.JobStatePending
+	job.Attempts = 0
+	job.StartedAt = nil
+	job.FinishedAt = nil
+	job.RetryAt = nil
+	job.Processed = 0
+	q.push(job)
+	q.save(job)
+	result := job.Copy()
+	q.mu.Unlock()
+
+	q.wakeUp()
+
+	return result, nil
+}
+
+// SetTotal фиксирует общее количество ссылок задачи
+func (q *Queue) SetTotal(jobID string, total int) {
+	q.updateJob(jobID, func(job *models.Job) {
+		job.TotalUrls = total
+	})
+}
+
+// SetProcessed фиксирует количество обработанных ссылок задачи
+func (q *Queue) SetProcessed(jobID string, processed int) {
+	q.updateJob(jobID, func(job *models.Job) {
+		job.Processed = processed
+	})
+}
+
+// AddError добавляет ошибку в сводку задачи
+func (q *Queue) AddError(jobID string, msg string) {
+	q.updateJob(jobID, func(job *models.Job) {
+		job.AddError(msg)
+	})
+}
+
+// AddStatuses прибавляет к сводке задачи счётчики статусов ссылок
+func (q *Queue) AddStatuses(jobID string, counts map[models.UrlStatus]int) {
+	q.updateJob(jobID, func(job *models.Job) {
+		job.AddStatuses(counts)
+	})
+}
+
+// Watcher запускает цикл обработки очереди, передавая задачи обработчикам их видов.
+// Завершается при отмене контекста, не прерывая уже запущенные задачи —
+// их дожидается Shutdown.
+func (q *Queue) Watcher(ctx context.Context) {
 	for {
+		// захватываем слот (не более MaxWorkers)
 		select {
 		case <-ctx.Done():
 			return
-		case req := <-q.ch:
-			q.semaphore <- struct{}{} // захватываем слот (не более MaxWorkers)
-			go func(r models.QueueRequest) {
-				defer func() { <-q.semaphore }()
-				q.processWithIDLock(ctx, r, executeUrls, executeAccount)
-			}(req)
+		case q.semaphore <- struct{}{}:
+		}
+
+		req, jobCtx, ok := q.next()
+		if !ok {
+			<-q.semaphore
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.notify:
+			}
+			continue
 		}
+
+		q.running.Add(1)
+		go func() {
+			defer q.running.Done()
+			defer func() { <-q.semaphore }()
+			q.process(jobCtx, req)
+		}()
 	}
 }
 
-// processWithIDLock ждёт, если задача с тем же ID уже выполняется.
-func (q *Queue) processWithIDLock(
-	ctx context.Context,
-	req models.QueueRequest,
-	executeUrls func(bool, string, string),
-	executeAccount func(bool, string, string),
-) {
-	for {
+// next достаёт следующую задачу и переводит её в running. Ключи блокировки
+// обходятся по кругу, внутри ключа задачи берутся в порядке постановки.
+// Пропускаются ключи, у которых уже выполняется задача, и задачи видов,
+// достигших лимита Concurrency, — они ждут в очереди, не занимая слот.
+func (q *Queue) next() (models.QueueRequest, context.Context, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, lockKey := range q.rotation {
+		if _, busy := q.busy[lockKey]; busy {
+			continue
+		}
+
+		ids := q.pending[lockKey]
+		job := q.jobs[ids[0]]
+		if k := q.kinds[job.Kind]; k.concurrency > 0 && q.kindRunning[job.Kind] >= k.concurrency {
+			continue
+		}
+
+		q.pendingCount--
+
+		// ключ уходит в конец круга, если у него остались задачи
+		q.rotation = append(q.rotation[:i:i], q.rotation[i+1:]...)
+		if len(ids) > 1 {
+			q.pending[lockKey] = ids[1:]
+			q.rotation = append(q.rotation, lockKey)
+		} else {
+			delete(q.pending, lockKey)
+		}
+
+		jobCtx, cancel := context.WithCancelCause(q.baseCtx)
+
+		now := time.Now()
+		job.State = models.JobStateRunning
+		job.StartedAt = &now
+		job.FinishedAt = nil
+		job.Attempts++
+		job.ResetSummary()
+		if lockKey != "" {
+			q.busy[lockKey] = struct{}{}
+		}
+		q.kindRunning[job.Kind]++
+		q.cancels[job.ID] = cancel
+		q.save(job)
+
+		return job.QueueRequest, jobCtx, true
+	}
+
+	return models.QueueRequest{}, nil, false
+}
+
+// process выполняет задачу, запущенную next, и фиксирует её итог
+func (q *Queue) process(jobCtx context.Context, req models.QueueRequest) {
+	defer func() {
+		// Освобождаем ключ и слот вида: следующая задача может быть взята в работу
+		q.mu.Lock()
+		delete(q.busy, req.LockKey)
+		q.kindRunning[req.Kind]--
+		q.mu.Unlock()
+
+		q.wakeUp()
+	}()
+
+	q.mu.Lock()
+	k := q.kinds[req.Kind]
+	q.mu.Unlock()
+
+	// Выполняем задачу
+	err := k.handle(jobCtx, req.ID, req.Payload)
+
+	q.mu.Lock()
+	if cancel, ok := q.cancels[req.ID]; ok {
+		cancel(nil)
+		delete(q.cancels, req.ID)
+	}
+	q.mu.Unlock()
+
+	q.updateJob(req.ID, func(job *models.Job) {
+		now := time.Now()
+		job.FinishedAt = &now
+
+		switch {
+		case errors.Is(context.Cause(jobCtx), models.ErrJobCancelled):
+			job.State = models.JobStateCancelled
+		case errors.Is(context.Cause(jobCtx), models.ErrShutdown):
+			job.State = models.JobStateInterrupted
+		case err == nil:
+			job.State = models.JobStateFinished
+		case !errors.Is(err, models.ErrTransient):
+			job.State = models.JobStateFailed
+			job.AddError(err.Error())
+		case job.Attempts > q.retry.MaxRetries:
+			job.State = models.JobStateDeadLetter
+			job.AddError(err.Error())
+			q.logger.Error("Job moved to dead letter",
+				slog.String("job_id", job.ID),
+				slog.String("kind", job.Kind),
+				slog.Int("attempts", job.Attempts),
+				slog.String("err", err.Error()),
+			)
+		default:
+			delay := q.retry.delay(job.Attempts)
+			retryAt := now.Add(delay)
+			job.State = models.JobStateRetrying
+			job.RetryAt = &retryAt
+			job.AddError(err.Error())
+			q.scheduleRetry(job.ID, delay)
+			q.logger.Warn("Job failed with transient error, retrying",
+				slog.String("job_id", job.ID),
+				slog.Int("attempt", job.Attempts),
+				slog.Duration("delay", delay),
+				slog.String("err", err.Error()),
+			)
+		}
+	})
+}
+
+// scheduleRetry возвращает задачу в очередь через wait, если к тому моменту
+// она всё ещё ждёт повтора. Вызывается под q.mu.
+func (q *Queue) scheduleRetry(jobID string, wait time.Duration) {
+	time.AfterFunc(wait, func() {
+		if q.baseCtx.Err() != nil {
+			// сервис останавливается — задача восстановится из хранилища при старте
+			return
+		}
+
 		q.mu.Lock()
-		if _, running := q.locks[req.SpreadsheetID]; !running {
-			// ID свободен — занимаем
-			q.locks[req.SpreadsheetID] = make(chan struct{})
+		job, ok := q.jobs[jobID]
+		if !ok || job.State != models.JobStateRetrying {
 			q.mu.Unlock()
-			break
+			return
 		}
-		// ID занят — берём канал и ждём снаружи мьютекса
-		wait := q.locks[req.SpreadsheetID]
+
+		job.State = models.JobStatePending
+		job.RetryAt = nil
+		q.push(job)
+		q.save(job)
 		q.mu.Unlock()
 
-		select {
-		case <-ctx.Done():
+		q.wakeUp()
+	})
+}
+
+// Shutdown ждёт завершения выполняющихся задач не дольше drainTimeout.
+// По истечении окна задачи отменяются: они сохраняют собранные результаты,
+// закрывают строку прогресса и помечаются interrupted.
+// Вызывается после остановки Watcher.
+func (q *Queue) Shutdown(drainTimeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// останавливаем таймеры повторов, задачи в retrying восстановятся при старте
+		q.baseCancel(models.ErrShutdown)
+		q.logger.Info("Queue drained")
+		return
+	case <-time.After(drainTimeout):
+	}
+
+	q.logger.Warn("Queue drain timeout exceeded, interrupting running jobs",
+		slog.Duration("drain_timeout", drainTimeout),
+	)
+	q.baseCancel(models.ErrShutdown)
+
+	<-done
+	q.logger.Info("Queue stopped")
+}
+
+func (q *Queue) updateJob(jobID string, update func(job *models.Job)) {
+	q.mu.Lock()
+	job, ok := q.jobs[jobID]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+
+	update(job)
+	q.save(job)
+	q.mu.Unlock()
+}
+
+// save сохраняет снимок задачи. Вызывается под q.mu, чтобы снимки одной задачи
+// попадали в хранилище в порядке изменений.
+func (q *Queue) save(job *models.Job) {
+	if err := q.storage.Save(job); err != nil {
+		q.logger.Error("Failed to save job",
+			slog.String("job_id", job.ID),
+			slog.String("err", err.Error()),
+		)
+	}
+}
+
+// push ставит ожидающую задачу в конец очереди её ключа блокировки. Вызывается под q.mu.
+func (q *Queue) push(job *models.Job) {
+	if _, ok := q.pending[job.LockKey]; !ok {
+		q.rotation = append(q.rotation, job.LockKey)
+	}
+
+	q.pending[job.LockKey] = append(q.pending[job.LockKey], job.ID)
+	q.pendingCount++
+}
+
+// remove убирает ожидающую задачу из очереди её ключа блокировки. Вызывается под q.mu.
+func (q *Queue) remove(job *models.Job) {
+	ids := q.pending[job.LockKey]
+	for i, id := range ids {
+		if id != job.ID {
+			continue
+		}
+
+		q.pendingCount--
+		if len(ids) > 1 {
+			q.pending[job.LockKey] = append(ids[:i:i], ids[i+1:]...)
 			return
-		case <-wait:
-			// предыдущая задача завершилась, пробуем снова
 		}
+
+		delete(q.pending, job.LockKey)
+		for j, lockKey := range q.rotation {
+			if lockKey == job.LockKey {
+				q.rotation = append(q.rotation[:j:j], q.rotation[j+1:]...)
+				break
+			}
+		}
+		return
 	}
+}
 
-	// Выполняем задачу
-	if req.Type == 0 {
-		executeUrls(req.IsSelected, req.SheetName, req.SpreadsheetID)
-	} else if req.Type == 1 {
-		executeAccount(req.IsSelected, req.SheetName, req.SpreadsheetID)
+// findPending ищет ожидающую задачу вида с тем же ключом дедупликации. Вызывается под q.mu.
+func (q *Queue) findPending(kindName, dedupKey string) *models.Job {
+	if dedupKey == "" {
+		return nil
 	}
 
-	// Освобождаем ID и уведомляем ожидающих
-	q.mu.Lock()
-	ch := q.locks[req.SpreadsheetID]
-	delete(q.locks, req.SpreadsheetID)
-	q.mu.Unlock()
+	for _, ids := range q.pending {
+		for _, id := range ids {
+			if job := q.jobs[id]; job.Kind == kindName && job.DedupKey == dedupKey {
+				return job
+			}
+		}
+	}
+
+	return nil
+}
+
+// wakeUp будит Watcher, если он ждёт новых задач
+func (q *Queue) wakeUp() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
 
-	close(ch) // все ожидающие этот ID разблокируются и попробуют снова
+// trimHistory удаляет самые старые завершённые задачи сверх jobsHistorySize.
+// Вызывается под q.mu.
+func (q *Queue) trimHistory() {
+	if len(q.jobs) <= jobsHistorySize {
+		return
+	}
+
+	finished := make([]*models.Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if !job.IsActive() {
+			finished = append(finished, job)
+		}
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.Before(finished[j].CreatedAt)
+	})
+
+	for _, job := range finished {
+		if len(q.jobs) <= jobsHistorySize {
+			return
+		}
+		delete(q.jobs, job.ID)
+	}
 }
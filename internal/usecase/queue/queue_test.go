@@ -0,0 +1,569 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+func TestQueue_JobLifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		execErr   error
+		wantState models.JobState
+	}{
+		{
+			name:      "urls finished",
+			kind:      models.JobKindParseUrls,
+			wantState: models.JobStateFinished,
+		},
+		{
+			name:      "account failed",
+			kind:      models.JobKindParseAccount,
+			execErr:   errors.New("sheets unavailable"),
+			wantState: models.JobStateFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			execute := func(_ context.Context, jobID string, _ models.SheetPayload) error {
+				q.SetTotal(jobID, 10)
+				q.SetProcessed(jobID, 7)
+				return tt.execErr
+			}
+			registerSheetKinds(q, execute)
+			go q.Watcher(ctx)
+
+			id, err := q.Enqueue(tt.kind, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+
+			job := waitJob(t, q, id, tt.wantState)
+			if job.TotalUrls != 10 || job.Processed != 7 {
+				t.Errorf("job totals = %d/%d, want 7/10", job.Processed, job.TotalUrls)
+			}
+			if job.StartedAt == nil || job.FinishedAt == nil {
+				t.Errorf("job timestamps not set: %+v", job)
+			}
+			if tt.execErr != nil && (job.ErrorsCount != 1 || job.Errors[0] != tt.execErr.Error()) {
+				t.Errorf("job errors = %v, want %v", job.Errors, tt.execErr)
+			}
+			if got := q.Jobs("sheet"); len(got) != 1 || got[0].ID != id {
+				t.Errorf("Jobs() = %v, want job %s", got, id)
+			}
+			if got := q.Jobs("other"); len(got) != 0 {
+				t.Errorf("Jobs(other) = %v, want empty", got)
+			}
+		})
+	}
+}
+
+func TestQueue_Restore(t *testing.T) {
+	tests := []struct {
+		name              string
+		resumeInterrupted bool
+		wantRunningState  models.JobState
+	}{
+		{
+			name:              "resume interrupted",
+			resumeInterrupted: true,
+			wantRunningState:  models.JobStateFinished,
+		},
+		{
+			name:              "mark interrupted",
+			resumeInterrupted: false,
+			wantRunningState:  models.JobStateInterrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheetPayload := json.RawMessage(`{"spreadsheet_id":"sheet","sheet_name":"list"}`)
+			storage := &memoryStorage{jobs: []*models.Job{
+				{QueueRequest: models.QueueRequest{ID: "pending", Kind: models.JobKindParseUrls, LockKey: "a", Payload: sheetPayload}, State: models.JobStatePending},
+				{QueueRequest: models.QueueRequest{ID: "running", Kind: models.JobKindParseUrls, LockKey: "b", Payload: sheetPayload}, State: models.JobStateRunning},
+				{QueueRequest: models.QueueRequest{ID: "done", Kind: models.JobKindParseUrls, LockKey: "c", Payload: sheetPayload}, State: models.JobStateFinished},
+				{QueueRequest: models.QueueRequest{ID: "unknown", Kind: "removed_kind"}, State: models.JobStatePending},
+			}}
+
+			q := NewQueue(testLogger(), storage, RetryPolicy{})
+
+			var (
+				mu       sync.Mutex
+				executed []string
+			)
+			registerSheetKinds(q, func(_ context.Context, jobID string, _ models.SheetPayload) error {
+				mu.Lock()
+				executed = append(executed, jobID)
+				mu.Unlock()
+				return nil
+			})
+
+			if err := q.Restore(tt.resumeInterrupted); err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go q.Watcher(ctx)
+
+			waitJob(t, q, "pending", models.JobStateFinished)
+			running := waitJob(t, q, "running", tt.wantRunningState)
+			if tt.resumeInterrupted && running.Restarts != 1 {
+				t.Errorf("running.Restarts = %d, want 1", running.Restarts)
+			}
+			// вид задачи больше не зарегистрирован
+			waitJob(t, q, "unknown", models.JobStateFailed)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range executed {
+				if id == "done" {
+					t.Errorf("finished job was executed again")
+				}
+			}
+		})
+	}
+}
+
+func TestQueue_Cancel(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	execute := func(ctx context.Context, jobID string, _ models.SheetPayload) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	registerSheetKinds(q, execute)
+	go q.Watcher(ctx)
+
+	running, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+	<-started
+	// задача той же таблицы ждёт освобождения ID
+	waiting, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+
+	if _, err := q.Cancel(waiting); err != nil {
+		t.Fatalf("Cancel(waiting) error = %v", err)
+	}
+	if _, err := q.Cancel(running); err != nil {
+		t.Fatalf("Cancel(running) error = %v", err)
+	}
+
+	waitJob(t, q, running, models.JobStateCancelled)
+	waitJob(t, q, waiting, models.JobStateCancelled)
+
+	if _, err := q.Cancel(running); !errors.Is(err, models.ErrJobNotActive) {
+		t.Errorf("Cancel(cancelled) error = %v, want %v", err, models.ErrJobNotActive)
+	}
+	if _, err := q.Cancel("missing"); !errors.Is(err, models.ErrJobNotFound) {
+		t.Errorf("Cancel(missing) error = %v, want %v", err, models.ErrJobNotFound)
+	}
+}
+
+func TestQueue_Shutdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocking  bool
+		wantState models.JobState
+	}{
+		{
+			name:      "drained",
+			blocking:  false,
+			wantState: models.JobStateFinished,
+		},
+		{
+			name:      "interrupted after drain timeout",
+			blocking:  true,
+			wantState: models.JobStateInterrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+			ctx, cancel := context.WithCancel(context.Background())
+
+			started := make(chan struct{})
+			execute := func(ctx context.Context, _ string, _ models.SheetPayload) error {
+				close(started)
+				if !tt.blocking {
+					time.Sleep(20 * time.Millisecond)
+					return nil
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}
+
+			registerSheetKinds(q, execute)
+
+			watcherDone := make(chan struct{})
+			go func() {
+				defer close(watcherDone)
+				q.Watcher(ctx)
+			}()
+
+			id, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+			<-started
+
+			cancel()
+			<-watcherDone
+			q.Shutdown(50 * time.Millisecond)
+
+			if job, _ := q.Job(id); job.State != tt.wantState {
+				t.Errorf("job state = %s, want %s", job.State, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestQueue_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		execErr      error
+		wantState    models.JobState
+		wantAttempts int
+	}{
+		{
+			name:         "transient error recovered",
+			failures:     2,
+			execErr:      fmt.Errorf("failed to find urls: %w", models.ErrTransient),
+			wantState:    models.JobStateFinished,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries exhausted",
+			failures:     10,
+			execErr:      fmt.Errorf("failed to find urls: %w", models.ErrTransient),
+			wantState:    models.JobStateDeadLetter,
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent error not retried",
+			failures:     10,
+			execErr:      errors.New("url column not found"),
+			wantState:    models.JobStateFailed,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{
+				MaxRetries: 2,
+				BaseDelay:  time.Millisecond,
+				MaxDelay:   5 * time.Millisecond,
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var calls atomic.Int32
+			execute := func(_ context.Context, _ string, _ models.SheetPayload) error {
+				if int(calls.Add(1)) <= tt.failures {
+					return tt.execErr
+				}
+				return nil
+			}
+			registerSheetKinds(q, execute)
+			go q.Watcher(ctx)
+
+			id, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+			job := waitJob(t, q, id, tt.wantState)
+			if job.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", job.Attempts, tt.wantAttempts)
+			}
+
+			deadLetter := q.DeadLetter("")
+			if got := len(deadLetter) == 1; got != (tt.wantState == models.JobStateDeadLetter) {
+				t.Errorf("DeadLetter() = %v", deadLetter)
+			}
+
+			_, err := q.Requeue(id)
+			if tt.wantState == models.JobStateFinished {
+				if !errors.Is(err, models.ErrJobNotRequeueable) {
+					t.Errorf("Requeue(finished) error = %v, want %v", err, models.ErrJobNotRequeueable)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Requeue() error = %v", err)
+			}
+
+			// после перезапуска исполнитель больше не падает
+			calls.Store(int32(tt.failures))
+			if job = waitJob(t, q, id, models.JobStateFinished); job.Attempts != 1 {
+				t.Errorf("Attempts after requeue = %d, want 1", job.Attempts)
+			}
+		})
+	}
+}
+
+func TestQueue_RetryResetsSummary(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// каждый запуск заново считает статусы всех ссылок, первый падает после записи
+	var calls atomic.Int32
+	execute := func(_ context.Context, jobID string, _ models.SheetPayload) error {
+		q.AddStatuses(jobID, map[models.UrlStatus]int{models.UrlStatusOK: 2, models.UrlStatusNotFound: 1})
+		q.AddError(jobID, "https://t.me/channel: not found")
+		if calls.Add(1) == 1 {
+			return fmt.Errorf("failed to insert data: %w", models.ErrTransient)
+		}
+		return nil
+	}
+	registerSheetKinds(q, execute)
+	go q.Watcher(ctx)
+
+	id, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+	job := waitJob(t, q, id, models.JobStateFinished)
+
+	if want := (map[models.UrlStatus]int{models.UrlStatusOK: 2, models.UrlStatusNotFound: 1}); !reflect.DeepEqual(job.Statuses, want) {
+		t.Errorf("Statuses = %v, want %v", job.Statuses, want)
+	}
+	if job.ErrorsCount != 1 || len(job.Errors) != 1 {
+		t.Errorf("errors = %d %v, want only errors of the last run", job.ErrorsCount, job.Errors)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}
+
+	for attempt, want := range map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 8 * time.Minute,
+		5: 10 * time.Minute,
+		9: 10 * time.Minute,
+	} {
+		if got := p.delay(attempt); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestQueue_EnqueueDeduplicate(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+
+	registerSheetKinds(q, nil)
+
+	first, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list", IsSelected: true})
+	second, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+	otherType, _ := q.Enqueue(models.JobKindParseAccount, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"})
+	otherSheet, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "other"})
+
+	if second != first {
+		t.Errorf("duplicate got job %s, want pending job %s", second, first)
+	}
+	if otherType == first || otherSheet == first {
+		t.Errorf("different type or sheet collapsed into %s", first)
+	}
+
+	// полный парсинг листа поглотил парсинг выделенных строк
+	if job, _ := q.Job(first); string(job.Payload) != `{"spreadsheet_id":"sheet","sheet_name":"list","is_selected":false}` {
+		t.Errorf("collapsed job payload = %s, want is_selected false", job.Payload)
+	}
+	if q.pendingCount != 3 {
+		t.Errorf("pendingCount = %d, want 3", q.pendingCount)
+	}
+
+	// выполняющаяся задача уже не дубликат ожидающей
+	q.next()
+	if again, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "list"}); again == first {
+		t.Errorf("request collapsed into running job %s", first)
+	}
+}
+
+func TestQueue_NextRoundRobin(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+
+	registerSheetKinds(q, nil)
+
+	names := make(map[string]string)
+	for _, r := range []struct{ spreadsheetID, sheetName string }{
+		{"a", "1"}, {"a", "2"}, {"a", "3"}, {"b", "1"}, {"c", "1"},
+	} {
+		id, _ := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: r.spreadsheetID, SheetName: r.sheetName})
+		names[id] = r.spreadsheetID + r.sheetName
+	}
+
+	// a занята выполняющейся a1 — её следующие задачи ждут, не блокируя b и c
+	var got []string
+	for i := 0; i < 3; i++ {
+		req, _, ok := q.next()
+		if !ok {
+			t.Fatalf("next() returned no job on step %d", i)
+		}
+		got = append(got, names[req.ID])
+	}
+	if _, _, ok := q.next(); ok {
+		t.Fatalf("next() returned job while every spreadsheet is busy")
+	}
+
+	for spreadsheetID := range q.busy {
+		delete(q.busy, spreadsheetID)
+	}
+	for {
+		req, _, ok := q.next()
+		if !ok {
+			break
+		}
+		got = append(got, names[req.ID])
+		delete(q.busy, req.LockKey)
+	}
+
+	want := []string{"a1", "b1", "c1", "a2", "a3"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestQueue_EnqueueFull(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+	registerSheetKinds(q, nil)
+
+	for i := 0; i < QueueSize; i++ {
+		if _, err := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Enqueue(%d) error = %v", i, err)
+		}
+	}
+
+	if _, err := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "overflow"}); !errors.Is(err, models.ErrQueueFull) {
+		t.Errorf("Enqueue() error = %v, want %v", err, models.ErrQueueFull)
+	}
+
+	// дубликат ожидающей задачи принимается и при полной очереди
+	if _, err := q.Enqueue(models.JobKindParseUrls, models.SheetPayload{SpreadsheetID: "sheet", SheetName: "0"}); err != nil {
+		t.Errorf("Enqueue(duplicate) error = %v", err)
+	}
+}
+
+func TestQueue_KindConcurrency(t *testing.T) {
+	q := NewQueue(testLogger(), &memoryStorage{}, RetryPolicy{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running, maxRunning atomic.Int32
+	Register(q, "report", KindOptions[string]{Concurrency: 2}, func(_ context.Context, _ string, _ string) error {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	})
+	go q.Watcher(ctx)
+
+	if _, err := q.Enqueue("unknown", "x"); !errors.Is(err, models.ErrUnknownJobKind) {
+		t.Errorf("Enqueue(unknown) error = %v, want %v", err, models.ErrUnknownJobKind)
+	}
+	if _, err := q.Enqueue("report", 42); !errors.Is(err, models.ErrInvalidPayload) {
+		t.Errorf("Enqueue(invalid payload) error = %v, want %v", err, models.ErrInvalidPayload)
+	}
+
+	var ids []string
+	for i := 0; i < 6; i++ {
+		id, err := q.Enqueue("report", fmt.Sprint(i))
+		if err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		waitJob(t, q, id, models.JobStateFinished)
+	}
+
+	if got := maxRunning.Load(); got != 2 {
+		t.Errorf("max concurrent jobs = %d, want 2", got)
+	}
+}
+
+// registerSheetKinds регистрирует парсинги листов с настройками как в main
+func registerSheetKinds(q *Queue, handler Handler[models.SheetPayload]) {
+	if handler == nil {
+		handler = func(context.Context, string, models.SheetPayload) error { return nil }
+	}
+
+	opts := KindOptions[models.SheetPayload]{
+		LockKey:  models.SheetPayload.LockKey,
+		DedupKey: models.SheetPayload.DedupKey,
+		Merge:    models.SheetPayload.Merge,
+		Validate: models.SheetPayload.Validate,
+	}
+	Register(q, models.JobKindParseUrls, opts, handler)
+	Register(q, models.JobKindParseAccount, opts, handler)
+}
+
+func waitJob(t *testing.T, q *Queue, id string, state models.JobState) *models.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := q.Job(id); ok && job.State == state {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	job, _ := q.Job(id)
+	t.Fatalf("job %s did not reach state %s: %+v", id, state, job)
+	return nil
+}
+
+type memoryStorage struct {
+	mu   sync.Mutex
+	jobs []*models.Job
+}
+
+func (s *memoryStorage) Save(job *models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job.Copy())
+	return nil
+}
+
+func (s *memoryStorage) Load() ([]*models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.jobs, nil
+}
+
+func (s *memoryStorage) Rewrite(jobs []*models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = jobs
+	return nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
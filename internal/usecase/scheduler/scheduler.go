@@ -0,0 +1,303 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"inst_parser/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// minInterval минимальный интервал "every": каждый запуск тратит квоты API
+const minInterval = 10 * time.Minute
+
+type (
+	Enqueuer interface {
+		Enqueue(kind string, payload any) (string, error)
+	}
+
+	Storage interface {
+		Load() ([]*models.Schedule, error)
+		Save(schedules []*models.Schedule) error
+	}
+)
+
+// Scheduler ставит в очередь парсинг таблиц по расписанию
+type Scheduler struct {
+	logger   *slog.Logger
+	storage  Storage
+	enqueuer Enqueuer
+	notify   chan struct{}
+	now      func() time.Time
+
+	mu        sync.Mutex
+	schedules map[string]*models.Schedule
+	specs     map[string]Spec
+}
+
+func NewScheduler(logger *slog.Logger, storage Storage, enqueuer Enqueuer) *Scheduler {
+	return &Scheduler{
+		logger:    logger,
+		storage:   storage,
+		enqueuer:  enqueuer,
+		notify:    make(chan struct{}, 1),
+		now:       time.Now,
+		schedules: make(map[string]*models.Schedule),
+		specs:     make(map[string]Spec),
+	}
+}
+
+// Restore загружает расписания из хранилища. Запуски, пропущенные пока сервис
+// был остановлен, выполняются один раз сразу после старта Run.
+func (s *Scheduler) Restore() error {
+	schedules, err := s.storage.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load schedules: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, schedule := range schedules {
+		spec, err := ParseSpec(schedule.Spec)
+		if err != nil {
+			s.logger.Error("Skipping schedule with invalid spec",
+				slog.String("schedule_id", schedule.ID),
+				slog.String("spec", schedule.Spec),
+				slog.String("err", err.Error()),
+			)
+			continue
+		}
+
+		if schedule.NextRunAt.IsZero() {
+			schedule.NextRunAt = spec.Next(s.now())
+		}
+
+		s.schedules[schedule.ID] = schedule
+		s.specs[schedule.ID] = spec
+	}
+
+	s.logger.Info("Schedules restored", slog.Int("schedules", len(s.schedules)))
+
+	return nil
+}
+
+// Add проверяет правило и регистрирует новое расписание
+func (s *Scheduler) Add(schedule models.Schedule) (*models.Schedule, error) {
+	if schedule.SpreadsheetID == "" || schedule.SheetName == "" {
+		return nil, fmt.Errorf("%w: spreadsheet_id and sheet_name are required", models.ErrInvalidSchedule)
+	}
+
+	if !models.IsSheetJobKind(schedule.Kind) {
+		return nil, fmt.Errorf("%w: unsupported kind %q", models.ErrInvalidSchedule, schedule.Kind)
+	}
+
+	if schedule.WriteBack && schedule.Kind != models.JobKindParseUrls {
+		return nil, fmt.Errorf("%w: write_back is supported only for %s", models.ErrInvalidSchedule, models.JobKindParseUrls)
+	}
+
+	if schedule.FullFeed && schedule.Kind != models.JobKindParseAccount {
+		return nil, fmt.Errorf("%w: full_feed is supported only for %s", models.ErrInvalidSchedule, models.JobKindParseAccount)
+	}
+
+	if !schedule.Window.IsZero() && schedule.Kind != models.JobKindParseAccount {
+		return nil, fmt.Errorf("%w: since and until are supported only for %s", models.ErrInvalidSchedule, models.JobKindParseAccount)
+	}
+
+	spec, err := ParseSpec(schedule.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidSchedule, err.Error())
+	}
+
+	now := s.now()
+	schedule.ID = uuid.NewString()
+	schedule.CreatedAt = now
+	schedule.NextRunAt = spec.Next(now)
+	schedule.LastRunAt = nil
+	schedule.LastJobID = ""
+	schedule.LastError = ""
+
+	s.mu.Lock()
+	s.schedules[schedule.ID] = &schedule
+	s.specs[schedule.ID] = spec
+	if err = s.persist(); err != nil {
+		delete(s.schedules, schedule.ID)
+		delete(s.specs, schedule.ID)
+		s.mu.Unlock()
+		return nil, err
+	}
+	result := schedule.Copy()
+	s.mu.Unlock()
+
+	s.wakeUp()
+
+	return result, nil
+}
+
+// Delete удаляет расписание. Уже поставленные задачи не отменяются.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return models.ErrScheduleNotFound
+	}
+	spec := s.specs[id]
+
+	delete(s.schedules, id)
+	delete(s.specs, id)
+
+	if err := s.persist(); err != nil {
+		s.schedules[id] = schedule
+		s.specs[id] = spec
+		return err
+	}
+
+	return nil
+}
+
+// Schedule возвращает копию расписания по ID
+func (s *Scheduler) Schedule(id string) (*models.Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return nil, false
+	}
+
+	return schedule.Copy(), true
+}
+
+// Schedules возвращает расписания таблицы (или все, если spreadsheetID пустой) в порядке создания
+func (s *Scheduler) Schedules(spreadsheetID string) []*models.Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*models.Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		if spreadsheetID != "" && schedule.SpreadsheetID != spreadsheetID {
+			continue
+		}
+		result = append(result, schedule.Copy())
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result
+}
+
+// Run ждёт ближайшего запуска и ставит задачи в очередь до отмены контекста
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		s.runDue(s.now())
+
+		timer := time.NewTimer(s.untilNext())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-s.notify:
+			timer.Stop()
+		}
+	}
+}
+
+// runDue ставит в очередь все расписания, время которых наступило
+func (s *Scheduler) runDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for id, schedule := range s.schedules {
+		if schedule.NextRunAt.After(now) {
+			continue
+		}
+
+		runAt := now
+		schedule.LastRunAt = &runAt
+		schedule.NextRunAt = s.specs[id].Next(now)
+		changed = true
+
+		jobID, err := s.enqueuer.Enqueue(schedule.Kind, schedule.Payload())
+		if err != nil {
+			schedule.LastError = err.Error()
+			s.logger.Error("Failed to enqueue scheduled parsing",
+				slog.String("schedule_id", id),
+				slog.String("spreadsheet_id", schedule.SpreadsheetID),
+				slog.String("err", err.Error()),
+			)
+			continue
+		}
+
+		schedule.LastJobID = jobID
+		schedule.LastError = ""
+		s.logger.Info("Scheduled parsing enqueued",
+			slog.String("schedule_id", id),
+			slog.String("spreadsheet_id", schedule.SpreadsheetID),
+			slog.String("job_id", jobID),
+		)
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := s.persist(); err != nil {
+		s.logger.Error("Failed to save schedules", slog.String("err", err.Error()))
+	}
+}
+
+// untilNext время до ближайшего запуска. Без расписаний ждём уведомления от Add.
+func (s *Scheduler) untilNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := 24 * time.Hour
+	now := s.now()
+	for _, schedule := range s.schedules {
+		if d := schedule.NextRunAt.Sub(now); d < wait {
+			wait = d
+		}
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait
+}
+
+// persist сохраняет все расписания. Вызывается под s.mu.
+func (s *Scheduler) persist() error {
+	schedules := make([]*models.Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule.Copy())
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+
+	if err := s.storage.Save(schedules); err != nil {
+		return fmt.Errorf("failed to save schedules: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) wakeUp() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
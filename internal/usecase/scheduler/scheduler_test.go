@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"inst_parser/internal/models"
+)
+
+func TestScheduler_RunDue(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	storage := &memoryStorage{}
+	enqueuer := &fakeEnqueuer{}
+
+	s := NewScheduler(testLogger(), storage, enqueuer)
+	s.now = func() time.Time { return now }
+
+	schedule, err := s.Add(models.Schedule{SpreadsheetID: "sheet", SheetName: "list", Kind: models.JobKindParseAccount, Spec: "every 6 hours"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if want := now.Add(6 * time.Hour); !schedule.NextRunAt.Equal(want) {
+		t.Errorf("NextRunAt = %v, want %v", schedule.NextRunAt, want)
+	}
+
+	// время ещё не наступило
+	s.runDue(now.Add(time.Hour))
+	if len(enqueuer.requests) != 0 {
+		t.Fatalf("enqueued %d requests before due time", len(enqueuer.requests))
+	}
+
+	runAt := now.Add(6 * time.Hour)
+	s.runDue(runAt)
+	if len(enqueuer.requests) != 1 || enqueuer.kinds[0] != models.JobKindParseAccount || enqueuer.requests[0].SheetName != "list" {
+		t.Fatalf("enqueued = %+v, want one account request", enqueuer.requests)
+	}
+
+	got, _ := s.Schedule(schedule.ID)
+	if got.LastRunAt == nil || !got.LastRunAt.Equal(runAt) || got.LastJobID != "job-1" {
+		t.Errorf("last run = %v/%s, want %v/job-1", got.LastRunAt, got.LastJobID, runAt)
+	}
+	if want := runAt.Add(6 * time.Hour); !got.NextRunAt.Equal(want) {
+		t.Errorf("NextRunAt = %v, want %v", got.NextRunAt, want)
+	}
+
+	// ошибка постановки сохраняется, расписание продолжает работать
+	enqueuer.err = errors.New("queue is full")
+	s.runDue(got.NextRunAt)
+	got, _ = s.Schedule(schedule.ID)
+	if got.LastError != "queue is full" {
+		t.Errorf("LastError = %q, want %q", got.LastError, "queue is full")
+	}
+
+	if len(storage.schedules) != 1 || storage.schedules[0].LastError != "queue is full" {
+		t.Errorf("stored schedules = %+v, want persisted last error", storage.schedules)
+	}
+}
+
+func TestScheduler_RestoreMissedRun(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	storage := &memoryStorage{schedules: []*models.Schedule{
+		{ID: "missed", SpreadsheetID: "a", SheetName: "list", Kind: models.JobKindParseUrls, Spec: "every 6 hours", NextRunAt: now.Add(-3 * time.Hour)},
+		{ID: "future", SpreadsheetID: "b", SheetName: "list", Kind: models.JobKindParseUrls, Spec: "every 6 hours", NextRunAt: now.Add(time.Hour)},
+		{ID: "broken", SpreadsheetID: "c", SheetName: "list", Kind: models.JobKindParseUrls, Spec: "sometimes"},
+	}}
+	enqueuer := &fakeEnqueuer{}
+
+	s := NewScheduler(testLogger(), storage, enqueuer)
+	s.now = func() time.Time { return now }
+	if err := s.Restore(); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if got := s.Schedules(""); len(got) != 2 {
+		t.Fatalf("Schedules() = %d, want 2 valid schedules", len(got))
+	}
+
+	s.runDue(now)
+	if len(enqueuer.requests) != 1 || enqueuer.requests[0].SpreadsheetID != "a" {
+		t.Fatalf("enqueued = %+v, want only missed schedule", enqueuer.requests)
+	}
+
+	if err := s.Delete("missed"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete("missed"); !errors.Is(err, models.ErrScheduleNotFound) {
+		t.Errorf("Delete(deleted) error = %v, want %v", err, models.ErrScheduleNotFound)
+	}
+}
+
+func TestScheduler_AddWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	enqueuer := &fakeEnqueuer{}
+
+	s := NewScheduler(testLogger(), &memoryStorage{}, enqueuer)
+	s.now = func() time.Time { return now }
+
+	window, err := models.ParseDateWindow("2024-04-01", "2024-04-30")
+	if err != nil {
+		t.Fatalf("ParseDateWindow() error = %v", err)
+	}
+
+	_, err = s.Add(models.Schedule{SpreadsheetID: "sheet", SheetName: "list", Kind: models.JobKindParseUrls, Spec: "every 6 hours", Window: window})
+	if !errors.Is(err, models.ErrInvalidSchedule) {
+		t.Errorf("Add(parse_urls with window) error = %v, want %v", err, models.ErrInvalidSchedule)
+	}
+
+	if _, err = s.Add(models.Schedule{SpreadsheetID: "sheet", SheetName: "list", Kind: models.JobKindParseAccount, Spec: "every 6 hours", Window: window}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	s.runDue(now.Add(6 * time.Hour))
+	if len(enqueuer.requests) != 1 || enqueuer.requests[0].Window != window {
+		t.Errorf("enqueued = %+v, want account request with window %v", enqueuer.requests, window)
+	}
+}
+
+type fakeEnqueuer struct {
+	kinds    []string
+	requests []models.SheetPayload
+	err      error
+}
+
+func (e *fakeEnqueuer) Enqueue(kind string, payload any) (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+
+	e.kinds = append(e.kinds, kind)
+	e.requests = append(e.requests, payload.(models.SheetPayload))
+	return fmt.Sprintf("job-%d", len(e.requests)), nil
+}
+
+type memoryStorage struct {
+	schedules []*models.Schedule
+}
+
+func (s *memoryStorage) Load() ([]*models.Schedule, error) {
+	return s.schedules, nil
+}
+
+func (s *memoryStorage) Save(schedules []*models.Schedule) error {
+	s.schedules = schedules
+	return nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
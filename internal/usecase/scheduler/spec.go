@@ -0,0 +1,266 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // таймзоны вроде Europe/Moscow доступны и в образах без tzdata
+)
+
+// Spec правило расписания: возвращает ближайший запуск строго после after
+type Spec interface {
+	Next(after time.Time) time.Time
+}
+
+// ParseSpec разбирает правило расписания. Поддерживаются:
+//
+//	every 30 minutes | every 6 hours | every 2 days | every hour
+//	daily 09:00 [Europe/Moscow]
+//	cron из 5 полей "мин час день месяц день_недели", опционально с префиксом CRON_TZ=Europe/Moscow
+func ParseSpec(spec string) (Spec, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(spec)))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty schedule spec")
+	}
+
+	switch fields[0] {
+	case "every":
+		return parseEvery(fields[1:])
+	case "daily":
+		// таймзону берём из исходной строки: LoadLocation чувствителен к регистру
+		return parseDaily(strings.Fields(strings.TrimSpace(spec))[1:])
+	default:
+		return parseCron(strings.Fields(strings.TrimSpace(spec)))
+	}
+}
+
+type everySpec struct {
+	interval time.Duration
+}
+
+func (s everySpec) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+func parseEvery(fields []string) (Spec, error) {
+	n := 1
+	if len(fields) == 2 {
+		var err error
+		n, err = strconv.Atoi(fields[0])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", fields[0])
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected \"every N minutes|hours|days\"")
+	}
+
+	var unit time.Duration
+	switch strings.TrimSuffix(fields[0], "s") {
+	case "minute":
+		unit = time.Minute
+	case "hour":
+		unit = time.Hour
+	case "day":
+		unit = 24 * time.Hour
+	default:
+		return nil, fmt.Errorf("unknown interval unit %q", fields[0])
+	}
+
+	interval := time.Duration(n) * unit
+	if interval < minInterval {
+		return nil, fmt.Errorf("interval must be at least %s", minInterval)
+	}
+
+	return everySpec{interval: interval}, nil
+}
+
+type dailySpec struct {
+	hour, minute int
+	loc          *time.Location
+}
+
+func (s dailySpec) Next(after time.Time) time.Time {
+	local := after.In(s.loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, s.hour, s.minute, 0, 0, s.loc)
+	}
+
+	return next
+}
+
+func parseDaily(fields []string) (Spec, error) {
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("expected \"daily HH:MM [timezone]\"")
+	}
+
+	at, err := time.Parse("15:04", fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: expected HH:MM", fields[0])
+	}
+
+	loc := time.Local
+	if len(fields) == 2 {
+		if loc, err = time.LoadLocation(fields[1]); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", fields[1])
+		}
+	}
+
+	return dailySpec{hour: at.Hour(), minute: at.Minute(), loc: loc}, nil
+}
+
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64 // битовые маски допустимых значений
+	domAny, dowAny                bool
+	loc                           *time.Location
+}
+
+// cronSearchLimit сколько лет вперёд ищем запуск, чтобы не зациклиться на 30 февраля
+const cronSearchLimit = 5
+
+func (s cronSpec) Next(after time.Time) time.Time {
+	t := after.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchLimit, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches как в cron: если заданы и день месяца, и день недели, достаточно совпадения любого
+func (s cronSpec) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowOK
+	case s.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+func parseCron(fields []string) (Spec, error) {
+	loc := time.Local
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "CRON_TZ=") {
+		var err error
+		name := strings.TrimPrefix(fields[0], "CRON_TZ=")
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", name)
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("unsupported schedule spec: expected \"every N hours\", \"daily HH:MM [timezone]\" or 5-field cron")
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]string{"minute", "hour", "day of month", "month", "day of week"}
+
+	var masks [5]uint64
+	for i, field := range fields {
+		mask, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", names[i], field, err)
+		}
+		masks[i] = mask
+	}
+
+	// 7 — тоже воскресенье
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+
+	spec := cronSpec{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+		loc:    loc,
+	}
+
+	// например "0 0 30 2 *" — 30 февраля никогда не наступит
+	if spec.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule never fires")
+	}
+
+	return spec, nil
+}
+
+// parseCronField разбирает поле cron: "*", "5", "1-5", "*/15", "0-30/10", "1,15"
+func parseCronField(field string, min, max int) (uint64, error) {
+	var mask uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if rng, stepStr, ok := strings.Cut(part, "/"); ok {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			part = rng
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			loStr, hiStr, _ := strings.Cut(part, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(loStr)
+			hi, err2 = strconv.Atoi(hiStr)
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+
+	return mask, nil
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpec_Next(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+
+	type args struct {
+		spec  string
+		after time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "every 6 hours",
+			args: args{spec: "every 6 hours", after: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 1, 16, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "every hour",
+			args: args{spec: "Every Hour", after: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "daily later today",
+			args: args{spec: "daily 09:00 Europe/Moscow", after: time.Date(2024, 5, 1, 5, 0, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 1, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "daily already passed",
+			args: args{spec: "daily 09:00 Europe/Moscow", after: time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 2, 9, 0, 0, 0, moscow),
+		},
+		{
+			name: "cron every 15 minutes",
+			args: args{spec: "CRON_TZ=UTC */15 * * * *", after: time.Date(2024, 5, 1, 10, 31, 10, 0, time.UTC)},
+			want: time.Date(2024, 5, 1, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "cron weekdays",
+			// 4 мая 2024 — суббота
+			args: args{spec: "CRON_TZ=Europe/Moscow 30 8 * * 1-5", after: time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 6, 8, 30, 0, 0, moscow),
+		},
+		{
+			name: "cron sunday as 7",
+			args: args{spec: "CRON_TZ=UTC 0 12 * * 7", after: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 5, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "interval too small",
+			args:    args{spec: "every 1 minute"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown timezone",
+			args:    args{spec: "daily 09:00 Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "never fires",
+			args:    args{spec: "0 0 30 2 *"},
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			args:    args{spec: "sometimes"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec(tt.args.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := spec.Next(tt.args.after); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
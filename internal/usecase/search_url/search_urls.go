@@ -1,13 +1,16 @@
 package search_url
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"slices"
 	"strings"
+	"time"
 
 	"inst_parser/internal/models"
+	"inst_parser/internal/utils"
 
 	"google.golang.org/api/sheets/v4"
 )
@@ -21,10 +24,10 @@ func NewUrlsService(log *slog.Logger, sheetsService *sheets.Service) *UrlsServic
 	return &UrlsService{log: log, sheetsService: sheetsService}
 }
 
-func (s *UrlsService) FindUrls(isSelected bool, parsingTypes []models.ParsingType, sheetName, spreadsheetID string) ([]*models.UrlInfo, error) {
+func (s *UrlsService) FindUrls(ctx context.Context, isSelected bool, parsingTypes []models.ParsingType, sheetName, spreadsheetID string) ([]*models.UrlInfo, error) {
 	const urlSearchWord = "видео"
 
-	columnsPositions, err := s.findColumns(spreadsheetID, sheetName, urlSearchWord)
+	columnsPositions, err := s.findColumns(ctx, spreadsheetID, sheetName, urlSearchWord)
 	if err != nil {
 		return nil, err
 	}
@@ -33,16 +36,17 @@ func (s *UrlsService) FindUrls(isSelected bool, parsingTypes []models.ParsingTyp
 		columnsPositions.CheckboxColumnIndex = -1
 	}
 
-	return s.GetUrls(spreadsheetID, sheetName, columnsPositions, parsingTypes)
+	return s.GetUrls(ctx, spreadsheetID, sheetName, columnsPositions, parsingTypes)
 }
 
 func (s *UrlsService) AccountUrls(
+	ctx context.Context,
 	isSelected bool,
 	sheetName, spreadsheetID string,
 ) ([]*models.UrlInfo, error) {
 	const urlSearchWord = "аккаунт"
 
-	columnsPositions, err := s.findColumns(spreadsheetID, sheetName, urlSearchWord)
+	columnsPositions, err := s.findColumns(ctx, spreadsheetID, sheetName, urlSearchWord)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +56,7 @@ func (s *UrlsService) AccountUrls(
 	}
 
 	return s.GetUrls(
+		ctx,
 		spreadsheetID,
 		sheetName,
 		columnsPositions,
@@ -60,22 +65,16 @@ func (s *UrlsService) AccountUrls(
 			models.InstagramParsingType,
 			models.YoutubeParsingType,
 			models.TiktokParsingType,
+			models.TelegramParsingType,
+			models.RutubeParsingType,
 		})
 }
 
-func (s *UrlsService) findColumns(spreadsheetID, sheetName, urlWord string) (*models.ColumnPositions, error) {
-	// Получаем вторую строку (строка 2 в Sheets соответствует индексу 1)
-	readRange := fmt.Sprintf("%s!2:2", sheetName)
-	resp, err := s.sheetsService.Spreadsheets.Values.Get(spreadsheetID, readRange).Do()
+func (s *UrlsService) findColumns(ctx context.Context, spreadsheetID, sheetName, urlWord string) (*models.ColumnPositions, error) {
+	headerRow, err := s.headerRow(ctx, spreadsheetID, sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get values from list: %w", err)
-	}
-
-	if len(resp.Values) == 0 {
-		return nil, fmt.Errorf("second row if empty")
+		return nil, err
 	}
-
-	headerRow := resp.Values[0]
 	positions := &models.ColumnPositions{
 		URLColumnIndex:      -1,
 		CheckboxColumnIndex: -1,
@@ -102,9 +101,12 @@ func (s *UrlsService) findColumns(spreadsheetID, sheetName, urlWord string) (*mo
 			positions.URLColumnIndex = i + 1 // Преобразуем в 1-based индекс
 		}
 
-		// Поиск колонки "Парсинг" или "Select"
-		if strings.Contains(lowerValue, "парсинг") {
-			positions.CheckboxColumnIndex = i + 1 // Преобразуем в 1-based индекс
+		// Поиск колонки "Парсинг" или "Select": берём первую, заголовки метрик
+		// вроде "Статус парсинга" чекбоксом не считаем
+		if positions.CheckboxColumnIndex == -1 && strings.Contains(lowerValue, "парсинг") {
+			if _, isMetric := models.MetricColumnByHeader(cellValue); !isMetric {
+				positions.CheckboxColumnIndex = i + 1 // Преобразуем в 1-based индекс
+			}
 		}
 
 		// Поиск колонки "Парсинг" или "Select"
@@ -121,7 +123,56 @@ func (s *UrlsService) findColumns(spreadsheetID, sheetName, urlWord string) (*mo
 	return positions, nil
 }
 
+// MetricColumns ищет колонки метрик по заголовкам второй строки, как findColumns колонку ссылок
+func (s *UrlsService) MetricColumns(ctx context.Context, spreadsheetID, sheetName string) (models.MetricColumns, error) {
+	headerRow, err := s.headerRow(ctx, spreadsheetID, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(models.MetricColumns)
+	for i, cell := range headerRow {
+		cellValue, ok := cell.(string)
+		if !ok {
+			continue
+		}
+
+		metric, ok := models.MetricColumnByHeader(cellValue)
+		if !ok {
+			continue
+		}
+
+		// при повторе заголовка берём первую колонку
+		if _, exists := columns[metric]; !exists {
+			columns[metric] = i + 1 // Преобразуем в 1-based индекс
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("failed to find metric columns")
+	}
+
+	return columns, nil
+}
+
+// headerRow читает строку заголовков листа
+func (s *UrlsService) headerRow(ctx context.Context, spreadsheetID, sheetName string) ([]interface{}, error) {
+	// Получаем вторую строку (строка 2 в Sheets соответствует индексу 1)
+	readRange := fmt.Sprintf("%s!2:2", sheetName)
+	resp, err := s.sheetsService.Spreadsheets.Values.Get(spreadsheetID, readRange).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values from list: %w", err)
+	}
+
+	if len(resp.Values) == 0 {
+		return nil, fmt.Errorf("second row if empty")
+	}
+
+	return resp.Values[0], nil
+}
+
 func (s *UrlsService) GetUrls(
+	ctx context.Context,
 	spreadsheetID, sheetName string,
 	positions *models.ColumnPositions,
 	parsingTypes []models.ParsingType,
@@ -140,24 +191,24 @@ func (s *UrlsService) GetUrls(
 		startCol := min(positions.URLColumnIndex, positions.CheckboxColumnIndex)
 		endCol := max(positions.CountColumnIndex, positions.CountColumnIndex)
 
-		startLetter := getColumnLetter(startCol)
-		endLetter := getColumnLetter(endCol)
+		startLetter := utils.ColumnLetter(startCol)
+		endLetter := utils.ColumnLetter(endCol)
 		readRange = fmt.Sprintf("%s!%s3:%s", sheetName, startLetter, endLetter)
 	} else if positions.CheckboxColumnIndex > 0 {
 		// Если есть колонка чекбокса, читаем диапазон от минимальной до максимальной колонки
 		startCol := min(positions.URLColumnIndex, positions.CheckboxColumnIndex)
 		endCol := max(positions.URLColumnIndex, positions.CheckboxColumnIndex)
 
-		startLetter := getColumnLetter(startCol)
-		endLetter := getColumnLetter(endCol)
+		startLetter := utils.ColumnLetter(startCol)
+		endLetter := utils.ColumnLetter(endCol)
 		readRange = fmt.Sprintf("%s!%s3:%s", sheetName, startLetter, endLetter)
 	} else {
 		// Получаем только колонку с URL
-		colLetter := getColumnLetter(positions.URLColumnIndex)
+		colLetter := utils.ColumnLetter(positions.URLColumnIndex)
 		readRange = fmt.Sprintf("%s!%s3:%s", sheetName, colLetter, colLetter)
 	}
 
-	resp, err := s.sheetsService.Spreadsheets.Values.Get(spreadsheetID, readRange).Do()
+	resp, err := s.sheetsService.Spreadsheets.Values.Get(spreadsheetID, readRange).Context(ctx).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read data: %w", err)
 	}
@@ -229,17 +280,21 @@ func (s *UrlsService) GetUrls(
 		}
 
 		url = strings.TrimSpace(url)
-		countInt, err := strconv.Atoi(strings.TrimSpace(count))
-		if err != nil {
+		// глубина — число публикаций или число дней: 30d
+		countInt, window, ok := models.ParseAccountDepth(count, time.Now())
+		if !ok {
 			countInt = 12
 		}
 
-		if models.IsAvailableByParsingType(url, parsingTypes) {
+		// youtu.be и прочие короткие формы проверяем по каноническому виду,
+		// нераскрытые короткие ссылки — по платформе сокращателя
+		info := models.NewUrlInfo(url, countInt, rowIndex+3)
+		info.Window = window
+		platform, isShort := models.ShortLinkPlatform(url)
+		if models.IsAvailableByParsingType(info.FetchURL(), parsingTypes) ||
+			isShort && slices.Contains(parsingTypes, platform) {
 			// Добавляем URL в результат
-			urls = append(urls, &models.UrlInfo{
-				URL:   url,
-				Count: countInt,
-			})
+			urls = append(urls, info)
 		}
 	}
 
@@ -278,17 +333,3 @@ func parseCheckboxValue(cellValue interface{}) (bool, bool) {
 		return false, false
 	}
 }
-
-func getColumnLetter(colNumber int) string {
-	if colNumber <= 0 {
-		return ""
-	}
-
-	letter := ""
-	for colNumber > 0 {
-		colNumber--
-		letter = string(rune('A'+(colNumber%26))) + letter
-		colNumber = colNumber / 26
-	}
-	return letter
-}
@@ -1,6 +1,7 @@
 package search_url
 
 import (
+	"context"
 	"inst_parser/internal/models"
 	"log/slog"
 	"reflect"
@@ -86,7 +87,7 @@ func TestUrlsService_FindColumns(t *testing.T) {
 				log:           logger.NewLogger(),
 				sheetsService: tt.fields.sheetsService,
 			}
-			got, err := s.findColumns(tt.fields.spreadsheetID, tt.args.sheetName, tt.fields.urlSearchWord)
+			got, err := s.findColumns(context.Background(), tt.fields.spreadsheetID, tt.args.sheetName, tt.fields.urlSearchWord)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("findColumns() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -163,7 +164,7 @@ func TestUrlsService_GetUrls(t *testing.T) {
 				log:           tt.fields.log,
 				sheetsService: tt.fields.sheetsService,
 			}
-			got, err := s.GetUrls(tt.fields.spreadsheetID, tt.args.sheetName, tt.args.positions, tt.args.parsingTypes)
+			got, err := s.GetUrls(context.Background(), tt.fields.spreadsheetID, tt.args.sheetName, tt.args.positions, tt.args.parsingTypes)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetUrls() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -217,7 +218,7 @@ func TestUrlsService_FindUrls(t *testing.T) {
 				log:           tt.fields.log,
 				sheetsService: tt.fields.sheetsService,
 			}
-			got, err := s.FindUrls(tt.args.isSelected, tt.args.parsingTypes, tt.fields.spreadsheetID, tt.args.sheetName)
+			got, err := s.FindUrls(context.Background(), tt.args.isSelected, tt.args.parsingTypes, tt.fields.spreadsheetID, tt.args.sheetName)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("FindUrls() error = %v, wantErr %v", err, tt.wantErr)
 				return
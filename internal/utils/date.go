@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"inst_parser/internal/constants"
@@ -12,6 +14,12 @@ var validDateFormats = []string{
 	time.RFC3339,
 	constants.YoutubeParsingDateFormat,
 	constants.ParsingDateFormat,
+	constants.RutubeDateFormat,
+}
+
+// Moscow часовой пояс, в котором показываем даты
+func Moscow() *time.Location {
+	return moscow
 }
 
 func ParsingDate() string {
@@ -34,3 +42,34 @@ func FormatParsingDate(date string) string {
 func PublishDate(pubTime time.Time) string {
 	return pubTime.In(moscow).Format(time.DateTime)
 }
+
+// dayFormats форматы дня в запросах и ячейках листа
+var dayFormats = []string{time.DateOnly, "02.01.2006"}
+
+// ParseDay начало дня по Москве: 2024-05-01 или 01.05.2024
+func ParseDay(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, format := range dayFormats {
+		t, err := time.ParseInLocation(format, value, moscow)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD or DD.MM.YYYY", value)
+}
+
+// StartOfDay начало дня t по Москве
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(moscow)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, moscow)
+}
+
+// UnixTime время из unix timestamp; 0 и меньше — дата неизвестна
+func UnixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0)
+}
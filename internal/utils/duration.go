@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var isoDurationRe = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseISODuration разбирает длительность ISO 8601 из YouTube API: PT1H2M3S, P1DT2H, P0D
+func ParseISODuration(value string) (time.Duration, error) {
+	m := isoDurationRe.FindStringSubmatch(value)
+	if m == nil || value == "P" || value == "PT" {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", value)
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %w", value, err)
+		}
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
+
+// FormatDuration длительность для таблицы: 0:45, 12:03, 1:02:03
+func FormatDuration(d time.Duration) string {
+	total := int(d.Round(time.Second) / time.Second)
+	hours, minutes, seconds := total/3600, total%3600/60, total%60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISODuration(t *testing.T) {
+	type args struct {
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "short", args: args{value: "PT45S"}, want: 45 * time.Second},
+		{name: "video", args: args{value: "PT1H2M3S"}, want: time.Hour + 2*time.Minute + 3*time.Second},
+		{name: "long stream", args: args{value: "P1DT2H"}, want: 26 * time.Hour},
+		{name: "live now", args: args{value: "P0D"}, want: 0},
+		{name: "empty", args: args{value: ""}, wantErr: true},
+		{name: "garbage", args: args{value: "PT1X"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseISODuration(tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseISODuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseISODuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	type args struct {
+		d time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "seconds", args: args{d: 45 * time.Second}, want: "0:45"},
+		{name: "minutes", args: args{d: 12*time.Minute + 3*time.Second}, want: "12:03"},
+		{name: "hours", args: args{d: time.Hour + 2*time.Minute + 3*time.Second}, want: "1:02:03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDuration(tt.args.d); got != tt.want {
+				t.Errorf("FormatDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
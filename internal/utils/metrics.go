@@ -16,3 +16,12 @@ func GetVirality(shares, views int64) string {
 	}
 	return fmt.Sprintf("%.2f%%", float64(shares)/float64(views)*100)
 }
+
+// GetERByFollowers средняя вовлечённость публикации (likes+shares+comments) к числу подписчиков
+func GetERByFollowers(engagement, posts, followers int64) string {
+	if engagement <= 0 || posts <= 0 || followers <= 0 {
+		return "0"
+	}
+
+	return fmt.Sprintf("%.2f%%", float64(engagement)/float64(posts)/float64(followers)*100)
+}
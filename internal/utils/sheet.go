@@ -0,0 +1,31 @@
+package utils
+
+import "strings"
+
+// ColumnLetter переводит 1-based номер колонки в буквенное обозначение: 1 — A, 27 — AA
+func ColumnLetter(colNumber int) string {
+	if colNumber <= 0 {
+		return ""
+	}
+
+	letter := ""
+	for colNumber > 0 {
+		colNumber--
+		letter = string(rune('A'+(colNumber%26))) + letter
+		colNumber = colNumber / 26
+	}
+	return letter
+}
+
+// ColumnNumber переводит буквенное обозначение колонки в 1-based номер: A — 1, AA — 27.
+// Для пустой строки или не букв возвращает 0
+func ColumnNumber(letter string) int {
+	number := 0
+	for _, r := range strings.ToUpper(letter) {
+		if r < 'A' || r > 'Z' {
+			return 0
+		}
+		number = number*26 + int(r-'A'+1)
+	}
+	return number
+}
@@ -2,17 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "inst_parser/docs"
 	"inst_parser/internal/config"
 	"inst_parser/internal/constants"
 	"inst_parser/internal/handlers"
 	"inst_parser/internal/logger"
+	"inst_parser/internal/models"
+	"inst_parser/internal/repository/checkpoint"
 	"inst_parser/internal/repository/google_sheet"
+	"inst_parser/internal/repository/history"
+	"inst_parser/internal/repository/journal"
 	"inst_parser/internal/repository/progress"
 	"inst_parser/internal/repository/rapid"
+	"inst_parser/internal/repository/rutube"
+	"inst_parser/internal/repository/schedules"
+	"inst_parser/internal/repository/shortlink"
+	"inst_parser/internal/repository/telegram"
 	"inst_parser/internal/repository/tg"
 	"inst_parser/internal/repository/video_downloader"
 	"inst_parser/internal/repository/vk"
@@ -21,6 +35,7 @@ import (
 	"inst_parser/internal/usecase/parsing_account"
 	"inst_parser/internal/usecase/parsing_urls"
 	"inst_parser/internal/usecase/queue"
+	"inst_parser/internal/usecase/scheduler"
 	"inst_parser/internal/usecase/search_url"
 
 	"github.com/rs/cors"
@@ -38,19 +53,50 @@ import (
 // @host     hammerhead-app-xw9wl.ondigitalocean.app
 // @BasePath  /
 
+const httpShutdownTimeout = 30 * time.Second
+
 func main() {
 	cfg := config.MustLoad()
 	l := logger.NewLogger()
 
 	l.Info("Starting server")
 
-	queue := queue.NewQueue()
+	queueJournal, err := journal.NewJournal(cfg.Queue.JournalPath)
+	if err != nil {
+		log.Fatal("Failed to open queue journal:", err)
+	}
+	defer queueJournal.Close()
+
+	jobQueue := queue.NewQueue(l, queueJournal, queue.RetryPolicy{
+		MaxRetries: cfg.Queue.MaxRetries,
+		BaseDelay:  cfg.Queue.RetryBaseDelay,
+		MaxDelay:   cfg.Queue.RetryMaxDelay,
+	})
+
+	scheduleStore, err := schedules.NewStore(cfg.Queue.SchedulesPath)
+	if err != nil {
+		log.Fatal("Failed to open schedules:", err)
+	}
+
+	checkpointStore, err := checkpoint.NewStore(cfg.Queue.CheckpointsDir)
+	if err != nil {
+		log.Fatal("Failed to open checkpoints:", err)
+	}
+
+	historyStore, err := history.NewStore(cfg.History.Path, cfg.History.Retention)
+	if err != nil {
+		log.Fatal("Failed to open metrics history:", err)
+	}
+	defer historyStore.Close()
+
 	googleSheetRepo := google_sheet.NewRepository(cfg.GoogleDriveCredentials)
 	progressSrv := progress.NewProgressTracker(googleSheetRepo.SheetsService)
 	urlSrv := search_url.NewUrlsService(l, googleSheetRepo.SheetsService)
 	vkRepo := vk.NewRepository(l, cfg.VK.Token)
 	rapidRepo := rapid.NewRepository(cfg.Rapid.ApiKey, l, vkRepo)
 	youtubeRepo := youtube.NewYouTubeClient(l, cfg.Youtube.YoutubeToken)
+	telegramRepo := telegram.NewClient(l)
+	rutubeRepo := rutube.NewClient(l)
 	videoDownloaderRepo := video_downloader.NewRepository()
 
 	parsingUrlsUsecase := parsing_urls.NewUsecase(
@@ -62,6 +108,12 @@ func main() {
 		progressSrv,
 		youtubeRepo,
 		rapidRepo,
+		jobQueue,
+		checkpointStore,
+		shortlink.NewResolver(cfg.ShortLink.Timeout, cfg.ShortLink.MaxHops, cfg.ShortLink.CacheTTL),
+		telegramRepo,
+		rutubeRepo,
+		historyStore,
 	)
 
 	parsingAccountUsecase := parsing_account.NewUsecase(
@@ -74,26 +126,62 @@ func main() {
 		rapidRepo,
 		youtubeRepo,
 		rapidRepo,
+		jobQueue,
+		telegramRepo,
+		rutubeRepo,
+		historyStore,
+	)
+
+	// Виды задач регистрируются до восстановления очереди
+	sheetJobOptions := queue.KindOptions[models.SheetPayload]{
+		LockKey:  models.SheetPayload.LockKey,
+		DedupKey: models.SheetPayload.DedupKey,
+		Merge:    models.SheetPayload.Merge,
+		Validate: models.SheetPayload.Validate,
+	}
+	queue.Register(jobQueue, models.JobKindParseUrls, sheetJobOptions,
+		func(ctx context.Context, jobID string, p models.SheetPayload) error {
+			return parsingUrlsUsecase.ParseUrls(ctx, jobID, p.IsSelected, p.WriteBack, p.Upsert, p.SheetName, p.SpreadsheetID)
+		},
+	)
+	queue.Register(jobQueue, models.JobKindParseAccount, sheetJobOptions,
+		func(ctx context.Context, jobID string, p models.SheetPayload) error {
+			return parsingAccountUsecase.ParseAccount(ctx, jobID, p.IsSelected, p.FullFeed, p.Window, p.Upsert, p.SheetName, p.SpreadsheetID)
+		},
 	)
 
+	if err = jobQueue.Restore(cfg.Queue.ResumeInterrupted); err != nil {
+		log.Fatal("Failed to restore queue:", err)
+	}
+
+	scheduler := scheduler.NewScheduler(l, scheduleStore, jobQueue)
+	if err = scheduler.Restore(); err != nil {
+		log.Fatal("Failed to restore schedules:", err)
+	}
+
 	tgClient := tg.NewClient(cfg.Telegram.BotToken, cfg.Telegram.ChatID)
 	downloadVideosUsecase := download_videos.NewUsecase(l, videoDownloaderRepo, vkRepo, rapidRepo)
 
-	parsingUrlsHandler := handlers.NewParsingUrlsHandler(l, queue)
+	parsingUrlsHandler := handlers.NewParsingUrlsHandler(l, jobQueue)
 	clipMoneyParsingUrlHandler := handlers.NewClipMoneyParsingUrl(l, parsingUrlsUsecase)
-	parsingAccountHandler := handlers.NewParsingAccountsHandler(l, queue)
+	parsingAccountHandler := handlers.NewParsingAccountsHandler(l, jobQueue)
 	clipMoneyParsingAccountHandler := handlers.NewClipMoneyParsingAccount(l, parsingAccountUsecase)
 	downloadVideosHandler := handlers.NewDownloadVideos(l, downloadVideosUsecase)
 	messageHandler := handlers.NewMessageHandler(tgClient)
+	jobsHandler := handlers.NewJobsHandler(l, jobQueue)
+	schedulesHandler := handlers.NewSchedulesHandler(l, scheduler)
+	metricsHistoryHandler := handlers.NewMetricsHistoryHandler(l, historyStore)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	go queue.Watcher(
-		ctx,
-		parsingUrlsUsecase.ParseUrls,
-		parsingAccountUsecase.ParseAccount,
-	)
+	watcherDone := make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		jobQueue.Watcher(ctx)
+	}()
+
+	go scheduler.Run(ctx)
 
 	mux := http.NewServeMux()
 
@@ -104,6 +192,16 @@ func main() {
 	mux.HandleFunc(constants.DownloadVideos, downloadVideosHandler.DownloadVideos)
 	mux.HandleFunc(constants.DownloadVideosGet, downloadVideosHandler.DownloadVideosGet)
 	mux.HandleFunc(constants.MessageSend, messageHandler.Send)
+	mux.HandleFunc(constants.Jobs, jobsHandler.Jobs)
+	mux.HandleFunc(http.MethodGet+" "+constants.JobByID, jobsHandler.Job)
+	mux.HandleFunc(http.MethodDelete+" "+constants.JobByID, jobsHandler.CancelJob)
+	mux.HandleFunc(http.MethodGet+" "+constants.DeadLetterJobs, jobsHandler.DeadLetterJobs)
+	mux.HandleFunc(http.MethodPost+" "+constants.RequeueJob, jobsHandler.RequeueJob)
+	mux.HandleFunc(http.MethodPost+" "+constants.Schedules, schedulesHandler.CreateSchedule)
+	mux.HandleFunc(http.MethodGet+" "+constants.Schedules, schedulesHandler.Schedules)
+	mux.HandleFunc(http.MethodGet+" "+constants.ScheduleByID, schedulesHandler.Schedule)
+	mux.HandleFunc(http.MethodDelete+" "+constants.ScheduleByID, schedulesHandler.DeleteSchedule)
+	mux.HandleFunc(http.MethodGet+" "+constants.MetricsHistory, metricsHistoryHandler.MetricsHistory)
 	mux.HandleFunc("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
@@ -111,7 +209,7 @@ func main() {
 	// Настройка CORS
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"POST", "GET", "OPTIONS"},
+		AllowedMethods:   []string{"POST", "GET", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Accept", "Authorization"},
 		AllowCredentials: false,
 	})
@@ -123,7 +221,31 @@ func main() {
 		httpSwagger.URL("/swagger/doc.json"), // URL для вашей swagger документации
 	))
 
-	if err := http.ListenAndServe(":8080", handler); err != nil {
-		log.Fatal("Server failed to start:", err)
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: handler,
 	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed to start:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	l.Info("Shutting down server")
+
+	// Перестаём принимать запросы и дожидаемся текущих
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		l.Error("Failed to shutdown server", slog.String("err", err.Error()))
+	}
+
+	// Watcher больше не берёт задачи, выполняющимся даём окно на завершение
+	<-watcherDone
+	jobQueue.Shutdown(cfg.Queue.DrainTimeout)
+
+	l.Info("Server stopped")
 }